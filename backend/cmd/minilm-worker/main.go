@@ -0,0 +1,51 @@
+// Command minilm-worker runs minilm.EmbeddingService as a standalone
+// out-of-process backend, speaking the proto/backend.proto contract via
+// pkg/grpc/base over a Unix socket. models.Manager launches this binary
+// (path/args configured in ModelsConfig) and supervises it through
+// base.SupervisedWorker instead of loading ONNX Runtime directly into the
+// API server, so a crash in the embedding model doesn't take the server
+// down with it.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"alice-backend/internal/minilm"
+	"alice-backend/pkg/grpc/base"
+)
+
+func main() {
+	socketPath := flag.String("socket", "", "unix socket path to listen on")
+	modelPath := flag.String("model-path", "models/minilm", "MiniLM model directory")
+	executionProvider := flag.String("execution-provider", "auto", "ONNX Runtime execution provider")
+	flag.Parse()
+
+	if *socketPath == "" {
+		log.Fatal("minilm-worker: -socket is required")
+	}
+
+	config := &minilm.Config{
+		ModelPath:         *modelPath,
+		Dimension:         384,
+		ExecutionProvider: minilm.ExecutionProvider(*executionProvider),
+	}
+
+	service := minilm.NewEmbeddingService(config)
+	ctx := context.Background()
+	if err := service.Initialize(ctx); err != nil {
+		log.Fatalf("minilm-worker: failed to initialize embedding service: %v", err)
+	}
+
+	server, err := base.NewServer(minilm.NewBackendAdapter(service), "unix", *socketPath)
+	if err != nil {
+		log.Fatalf("minilm-worker: failed to start backend server: %v", err)
+	}
+	defer server.Close()
+
+	log.Printf("minilm-worker: listening on %s", server.Addr())
+	if err := server.Serve(); err != nil {
+		log.Fatalf("minilm-worker: serve failed: %v", err)
+	}
+}
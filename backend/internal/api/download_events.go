@@ -0,0 +1,149 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"alice-backend/internal/embedded"
+)
+
+// downloadEventHistoryLimit bounds how many past events DownloadEventHub
+// keeps in memory for late subscribers to replay via Last-Event-ID; once
+// exceeded the oldest events are dropped.
+const downloadEventHistoryLimit = 256
+
+// downloadEvent pairs a embedded.ProgressEvent with the monotonically
+// increasing sequence number a client sends back as Last-Event-ID to resume
+// a dropped SSE connection without missing anything.
+type downloadEvent struct {
+	Seq   int64                  `json:"seq"`
+	Event embedded.ProgressEvent `json:"event"`
+}
+
+// DownloadEventHub is an in-process pub/sub of model asset download/extract/
+// verify progress, implementing embedded.ProgressReporter so it can be
+// installed on models.Manager via SetProgressReporter. It fans every
+// reported event out to every current SSE subscriber, and keeps a bounded
+// backlog so a client that reconnects with Last-Event-ID doesn't miss events
+// that happened while it was disconnected.
+type DownloadEventHub struct {
+	mu      sync.Mutex
+	seq     int64
+	history []downloadEvent
+	subs    map[chan downloadEvent]struct{}
+}
+
+// NewDownloadEventHub creates an empty hub with no history and no subscribers.
+func NewDownloadEventHub() *DownloadEventHub {
+	return &DownloadEventHub{subs: make(map[chan downloadEvent]struct{})}
+}
+
+// Report implements embedded.ProgressReporter.
+func (h *DownloadEventHub) Report(event embedded.ProgressEvent) {
+	h.mu.Lock()
+	h.seq++
+	de := downloadEvent{Seq: h.seq, Event: event}
+	h.history = append(h.history, de)
+	if len(h.history) > downloadEventHistoryLimit {
+		h.history = h.history[len(h.history)-downloadEventHistoryLimit:]
+	}
+	subs := make([]chan downloadEvent, 0, len(h.subs))
+	for ch := range h.subs {
+		subs = append(subs, ch)
+	}
+	h.mu.Unlock()
+
+	for _, ch := range subs {
+		select {
+		case ch <- de:
+		default:
+			// Slow subscriber; drop rather than block the reporter.
+		}
+	}
+}
+
+// subscribe registers a new subscriber, returning every event recorded
+// after afterSeq (0 meaning "from the start of the current history") plus
+// the channel that future events are delivered on. Callers must call
+// unsubscribe when done.
+func (h *DownloadEventHub) subscribe(afterSeq int64) ([]downloadEvent, chan downloadEvent) {
+	ch := make(chan downloadEvent, 64)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var backlog []downloadEvent
+	for _, de := range h.history {
+		if de.Seq > afterSeq {
+			backlog = append(backlog, de)
+		}
+	}
+	h.subs[ch] = struct{}{}
+	return backlog, ch
+}
+
+func (h *DownloadEventHub) unsubscribe(ch chan downloadEvent) {
+	h.mu.Lock()
+	delete(h.subs, ch)
+	h.mu.Unlock()
+}
+
+// DownloadModelEvents streams model asset download/extract/verify progress
+// as Server-Sent Events. Each event's "id:" field is its sequence number;
+// a client that reconnects sends that back as the Last-Event-ID request
+// header to resume from where it left off instead of missing events.
+func (h *Handler) DownloadModelEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.writeError(w, r, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	var afterSeq int64
+	if lastID := r.Header.Get("Last-Event-ID"); lastID != "" {
+		if parsed, err := strconv.ParseInt(lastID, 10, 64); err == nil {
+			afterSeq = parsed
+		}
+	}
+
+	backlog, ch := h.downloadEvents.subscribe(afterSeq)
+	defer h.downloadEvents.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	writeEvent := func(de downloadEvent) bool {
+		data, err := json.Marshal(de.Event)
+		if err != nil {
+			return true
+		}
+		if _, err := fmt.Fprintf(w, "id: %d\ndata: %s\n\n", de.Seq, data); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+
+	for _, de := range backlog {
+		if !writeEvent(de) {
+			return
+		}
+	}
+
+	for {
+		select {
+		case de := <-ch:
+			if !writeEvent(de) {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
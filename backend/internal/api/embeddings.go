@@ -1,8 +1,11 @@
 package api
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -55,30 +58,48 @@ type SearchResponse struct {
 // GenerateEmbedding handles single embedding generation
 func (h *Handler) GenerateEmbedding(w http.ResponseWriter, r *http.Request) {
 	if !h.config.Features.Embeddings {
-		h.writeError(w, http.StatusServiceUnavailable, "Embeddings service is disabled")
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service is disabled")
 		return
 	}
 
-	embeddingService := h.modelManager.GetEmbeddingService()
+	embeddingService, err := h.modelManager.GetEmbeddingServiceFor(r.Context(), modelFromContext(r.Context()))
+	if err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
 	if embeddingService == nil || !embeddingService.IsReady() {
-		h.writeError(w, http.StatusServiceUnavailable, "Embeddings service is not ready")
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service is not ready")
+		return
+	}
+
+	monitor := h.modelManager.GetMonitor()
+	if !monitor.Allow("embeddings") {
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service is recovering from an error spike, try again shortly")
 		return
 	}
 
 	var req EmbeddingRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		h.writeError(w, r, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	if req.Text == "" {
-		h.writeError(w, http.StatusBadRequest, "Text is required")
+		h.writeError(w, r, http.StatusBadRequest, "Text is required")
 		return
 	}
 
+	recorder := h.modelManager.GetMetricsRecorder()
+	recorder.IncEmbeddingsQueueDepth()
+	defer recorder.DecEmbeddingsQueueDepth()
+
+	start := time.Now()
 	embedding, err := embeddingService.GenerateEmbedding(r.Context(), req.Text)
+	duration := time.Since(start)
+	monitor.RecordResult("embeddings", duration, err)
+	recorder.ObserveModelInference("embeddings", duration)
 	if err != nil {
-		h.writeError(w, http.StatusInternalServerError, "Embedding generation failed: "+err.Error())
+		h.writeError(w, r, http.StatusInternalServerError, "Embedding generation failed: "+err.Error())
 		return
 	}
 
@@ -91,30 +112,35 @@ func (h *Handler) GenerateEmbedding(w http.ResponseWriter, r *http.Request) {
 // GenerateEmbeddings handles batch embedding generation
 func (h *Handler) GenerateEmbeddings(w http.ResponseWriter, r *http.Request) {
 	if !h.config.Features.Embeddings {
-		h.writeError(w, http.StatusServiceUnavailable, "Embeddings service is disabled")
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service is disabled")
 		return
 	}
 
 	embeddingService := h.modelManager.GetEmbeddingService()
 	if embeddingService == nil || !embeddingService.IsReady() {
-		h.writeError(w, http.StatusServiceUnavailable, "Embeddings service is not ready")
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service is not ready")
 		return
 	}
 
 	var req BatchEmbeddingRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		h.writeError(w, r, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	if len(req.Texts) == 0 {
-		h.writeError(w, http.StatusBadRequest, "Texts array is required")
+		h.writeError(w, r, http.StatusBadRequest, "Texts array is required")
 		return
 	}
 
+	recorder := h.modelManager.GetMetricsRecorder()
+	recorder.IncEmbeddingsQueueDepth()
+	start := time.Now()
 	embeddings, err := embeddingService.GenerateEmbeddings(r.Context(), req.Texts)
+	recorder.ObserveModelInference("embeddings", time.Since(start))
+	recorder.DecEmbeddingsQueueDepth()
 	if err != nil {
-		h.writeError(w, http.StatusInternalServerError, "Batch embedding generation failed: "+err.Error())
+		h.writeError(w, r, http.StatusInternalServerError, "Batch embedding generation failed: "+err.Error())
 		return
 	}
 
@@ -123,33 +149,109 @@ func (h *Handler) GenerateEmbeddings(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// embeddingStreamResult is one line of GenerateEmbeddingsStream's NDJSON
+// response: the input's position in the request stream plus either its
+// embedding or, for a line that failed to decode or embed, an error.
+type embeddingStreamResult struct {
+	Index     int       `json:"index"`
+	Embedding []float32 `json:"embedding,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// GenerateEmbeddingsStream is the streaming counterpart to GenerateEmbeddings:
+// the request body is newline-delimited JSON, one {"text":"..."} object per
+// line, and each result is written back as its own NDJSON line as soon as
+// it's produced, via http.Flusher, rather than buffering the whole batch
+// into a single JSON array response.
+func (h *Handler) GenerateEmbeddingsStream(w http.ResponseWriter, r *http.Request) {
+	if !h.config.Features.Embeddings {
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service is disabled")
+		return
+	}
+
+	embeddingService := h.modelManager.GetEmbeddingService()
+	if embeddingService == nil || !embeddingService.IsReady() {
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service is not ready")
+		return
+	}
+
+	monitor := h.modelManager.GetMonitor()
+	if !monitor.Allow("embeddings") {
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service is recovering from an error spike, try again shortly")
+		return
+	}
+
+	recorder := h.modelManager.GetMetricsRecorder()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher, canFlush := w.(http.Flusher)
+
+	encoder := json.NewEncoder(w)
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for i := 0; scanner.Scan(); i++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var req EmbeddingRequest
+		result := embeddingStreamResult{Index: i}
+		if err := json.Unmarshal(line, &req); err != nil {
+			result.Error = "invalid JSON: " + err.Error()
+		} else {
+			recorder.IncEmbeddingsQueueDepth()
+			start := time.Now()
+			embedding, err := embeddingService.GenerateEmbedding(r.Context(), req.Text)
+			duration := time.Since(start)
+			monitor.RecordResult("embeddings", duration, err)
+			recorder.ObserveModelInference("embeddings", duration)
+			recorder.DecEmbeddingsQueueDepth()
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Embedding = embedding
+			}
+		}
+
+		if err := encoder.Encode(result); err != nil {
+			return
+		}
+		if canFlush {
+			flusher.Flush()
+		}
+	}
+}
+
 // ComputeSimilarity handles similarity computation
 func (h *Handler) ComputeSimilarity(w http.ResponseWriter, r *http.Request) {
 	if !h.config.Features.Embeddings {
-		h.writeError(w, http.StatusServiceUnavailable, "Embeddings service is disabled")
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service is disabled")
 		return
 	}
 
 	embeddingService := h.modelManager.GetEmbeddingService()
 	if embeddingService == nil || !embeddingService.IsReady() {
-		h.writeError(w, http.StatusServiceUnavailable, "Embeddings service is not ready")
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service is not ready")
 		return
 	}
 
 	var req SimilarityRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		h.writeError(w, r, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	if len(req.Embedding1) == 0 || len(req.Embedding2) == 0 {
-		h.writeError(w, http.StatusBadRequest, "Both embeddings are required")
+		h.writeError(w, r, http.StatusBadRequest, "Both embeddings are required")
 		return
 	}
 
 	similarity, err := embeddingService.ComputeSimilarity(r.Context(), req.Embedding1, req.Embedding2)
 	if err != nil {
-		h.writeError(w, http.StatusInternalServerError, "Similarity computation failed: "+err.Error())
+		h.writeError(w, r, http.StatusInternalServerError, "Similarity computation failed: "+err.Error())
 		return
 	}
 
@@ -161,29 +263,29 @@ func (h *Handler) ComputeSimilarity(w http.ResponseWriter, r *http.Request) {
 // SearchSimilar handles similarity search
 func (h *Handler) SearchSimilar(w http.ResponseWriter, r *http.Request) {
 	if !h.config.Features.Embeddings {
-		h.writeError(w, http.StatusServiceUnavailable, "Embeddings service is disabled")
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service is disabled")
 		return
 	}
 
 	embeddingService := h.modelManager.GetEmbeddingService()
 	if embeddingService == nil || !embeddingService.IsReady() {
-		h.writeError(w, http.StatusServiceUnavailable, "Embeddings service is not ready")
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service is not ready")
 		return
 	}
 
 	var req SearchRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		h.writeError(w, r, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	if len(req.QueryEmbedding) == 0 {
-		h.writeError(w, http.StatusBadRequest, "Query embedding is required")
+		h.writeError(w, r, http.StatusBadRequest, "Query embedding is required")
 		return
 	}
 
 	if len(req.CandidateEmbeddings) == 0 {
-		h.writeError(w, http.StatusBadRequest, "Candidate embeddings are required")
+		h.writeError(w, r, http.StatusBadRequest, "Candidate embeddings are required")
 		return
 	}
 
@@ -194,7 +296,7 @@ func (h *Handler) SearchSimilar(w http.ResponseWriter, r *http.Request) {
 		req.TopK,
 	)
 	if err != nil {
-		h.writeError(w, http.StatusInternalServerError, "Similarity search failed: "+err.Error())
+		h.writeError(w, r, http.StatusInternalServerError, "Similarity search failed: "+err.Error())
 		return
 	}
 
@@ -207,13 +309,13 @@ func (h *Handler) SearchSimilar(w http.ResponseWriter, r *http.Request) {
 // GetEmbeddingsInfo returns embeddings service information
 func (h *Handler) GetEmbeddingsInfo(w http.ResponseWriter, r *http.Request) {
 	if !h.config.Features.Embeddings {
-		h.writeError(w, http.StatusServiceUnavailable, "Embeddings service is disabled")
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service is disabled")
 		return
 	}
 
 	embeddingService := h.modelManager.GetEmbeddingService()
 	if embeddingService == nil {
-		h.writeError(w, http.StatusServiceUnavailable, "Embeddings service not available")
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service not available")
 		return
 	}
 
@@ -227,6 +329,7 @@ func (h *Handler) RegisterEmbeddingsRoutes(router *mux.Router) {
 
 	embeddingsRouter.HandleFunc("/generate", h.GenerateEmbedding).Methods("POST")
 	embeddingsRouter.HandleFunc("/batch", h.GenerateEmbeddings).Methods("POST")
+	embeddingsRouter.HandleFunc("/batch/stream", h.GenerateEmbeddingsStream).Methods("POST")
 	embeddingsRouter.HandleFunc("/similarity", h.ComputeSimilarity).Methods("POST")
 	embeddingsRouter.HandleFunc("/search", h.SearchSimilar).Methods("POST")
 	embeddingsRouter.HandleFunc("/info", h.GetEmbeddingsInfo).Methods("GET")
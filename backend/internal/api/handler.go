@@ -2,8 +2,10 @@ package api
 
 import (
 	"encoding/json"
+	"log/slog"
 	"net/http"
 
+	"alice-backend/internal/apierr"
 	"alice-backend/internal/config"
 	"alice-backend/internal/models"
 )
@@ -12,16 +14,33 @@ import (
 type Handler struct {
 	config       *config.Config
 	modelManager *models.Manager
+
+	// downloadEvents fans out asset download/extract/verify progress to GET
+	// /api/models/download-events subscribers. It's also installed on
+	// modelManager as its embedded.ProgressReporter (see main.go), so it
+	// must be constructed before modelManager.Initialize runs.
+	downloadEvents *DownloadEventHub
 }
 
-// NewHandler creates a new API handler
-func NewHandler(config *config.Config, modelManager *models.Manager) *Handler {
+// NewHandler creates a new API handler. downloadEvents is the hub the
+// caller installed on modelManager via Manager.SetProgressReporter before
+// calling Initialize, so download-events subscribers see the startup
+// EnsureAssets run too.
+func NewHandler(config *config.Config, modelManager *models.Manager, downloadEvents *DownloadEventHub) *Handler {
 	return &Handler{
-		config:       config,
-		modelManager: modelManager,
+		config:         config,
+		modelManager:   modelManager,
+		downloadEvents: downloadEvents,
 	}
 }
 
+// ModelManager returns the handler's model manager, for code outside
+// internal/api that needs access to it - e.g. server.go wiring
+// MetricsMiddleware from the manager's shared metrics.Recorder.
+func (h *Handler) ModelManager() *models.Manager {
+	return h.modelManager
+}
+
 // writeSuccess writes a successful JSON response
 func (h *Handler) writeSuccess(w http.ResponseWriter, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -32,13 +51,35 @@ func (h *Handler) writeSuccess(w http.ResponseWriter, data interface{}) {
 	})
 }
 
-// writeError writes an error JSON response
-func (h *Handler) writeError(w http.ResponseWriter, statusCode int, message string) {
+// writeError writes a JSON error response for a plain status code/message
+// pair, the common case across handlers. It's a thin wrapper over
+// writeAPIError that fills in a generic code derived from statusCode; call
+// sites that already have a richer *apierr.Error (a known failure mode with
+// its own code and captured stack) should call writeAPIError directly.
+func (h *Handler) writeError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	h.writeAPIError(w, r, apierr.New(apierr.CodeForStatus(statusCode), statusCode, message))
+}
+
+// writeAPIError writes the uniform JSON error envelope
+// {"success":false,"error":{"code","message","request_id"}}. Server errors
+// (status >= 500) are also logged, with their stack, alongside the request
+// id so the two can be correlated; client errors are not logged.
+func (h *Handler) writeAPIError(w http.ResponseWriter, r *http.Request, err *apierr.Error) {
+	requestID := RequestIDFromContext(r.Context())
+
+	if err.HTTPStatus >= http.StatusInternalServerError {
+		slog.Error("API error", "code", err.Code, "message", err.Message, "request_id", requestID, "stack", err.StackString())
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
+	w.WriteHeader(err.HTTPStatus)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success": false,
-		"error":   message,
+		"error": map[string]interface{}{
+			"code":       err.Code,
+			"message":    err.Message,
+			"request_id": requestID,
+		},
 	})
 }
 
@@ -67,16 +108,35 @@ func (h *Handler) GetConfig(w http.ResponseWriter, r *http.Request) {
 	h.writeSuccess(w, h.config)
 }
 
+// GetStatus returns service readiness plus, when metrics are enabled,
+// per-backend latency/error-rate/circuit-breaker status.
+func (h *Handler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	h.writeSuccess(w, h.modelManager.GetStatus())
+}
+
+// Metrics serves the backend monitor's snapshot, plus the request/inference
+// counters and histograms tracked by metrics.Recorder (see
+// ModelManager().GetMetricsRecorder), in Prometheus text exposition format.
+func (h *Handler) Metrics(w http.ResponseWriter, r *http.Request) {
+	if !h.config.Features.EnableMetrics {
+		h.writeError(w, r, http.StatusServiceUnavailable, "Metrics are disabled")
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	h.modelManager.GetMonitor().WritePrometheus(w)
+	h.modelManager.GetMetricsRecorder().WritePrometheus(w)
+}
+
 // STTReady checks if STT service is ready
 func (h *Handler) STTReady(w http.ResponseWriter, r *http.Request) {
 	if !h.config.Features.STT {
-		h.writeError(w, http.StatusServiceUnavailable, "STT service is disabled")
+		h.writeError(w, r, http.StatusServiceUnavailable, "STT service is disabled")
 		return
 	}
 
 	sttService := h.modelManager.GetSTTService()
 	if sttService == nil || !sttService.IsReady() {
-		h.writeError(w, http.StatusServiceUnavailable, "STT service is not ready")
+		h.writeError(w, r, http.StatusServiceUnavailable, "STT service is not ready")
 		return
 	}
 
@@ -86,13 +146,13 @@ func (h *Handler) STTReady(w http.ResponseWriter, r *http.Request) {
 // STTInfo returns STT service information
 func (h *Handler) STTInfo(w http.ResponseWriter, r *http.Request) {
 	if !h.config.Features.STT {
-		h.writeError(w, http.StatusServiceUnavailable, "STT service is disabled")
+		h.writeError(w, r, http.StatusServiceUnavailable, "STT service is disabled")
 		return
 	}
 
 	sttService := h.modelManager.GetSTTService()
 	if sttService == nil {
-		h.writeError(w, http.StatusServiceUnavailable, "STT service not available")
+		h.writeError(w, r, http.StatusServiceUnavailable, "STT service not available")
 		return
 	}
 
@@ -103,13 +163,13 @@ func (h *Handler) STTInfo(w http.ResponseWriter, r *http.Request) {
 // TTSReady checks if TTS service is ready
 func (h *Handler) TTSReady(w http.ResponseWriter, r *http.Request) {
 	if !h.config.Features.TTS {
-		h.writeError(w, http.StatusServiceUnavailable, "TTS service is disabled")
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service is disabled")
 		return
 	}
 
 	ttsService := h.modelManager.GetTTSService()
 	if ttsService == nil || !ttsService.IsReady() {
-		h.writeError(w, http.StatusServiceUnavailable, "TTS service is not ready")
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service is not ready")
 		return
 	}
 
@@ -119,13 +179,13 @@ func (h *Handler) TTSReady(w http.ResponseWriter, r *http.Request) {
 // TTSInfo returns TTS service information
 func (h *Handler) TTSInfo(w http.ResponseWriter, r *http.Request) {
 	if !h.config.Features.TTS {
-		h.writeError(w, http.StatusServiceUnavailable, "TTS service is disabled")
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service is disabled")
 		return
 	}
 
 	ttsService := h.modelManager.GetTTSService()
 	if ttsService == nil {
-		h.writeError(w, http.StatusServiceUnavailable, "TTS service not available")
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service not available")
 		return
 	}
 
@@ -136,13 +196,13 @@ func (h *Handler) TTSInfo(w http.ResponseWriter, r *http.Request) {
 // EmbeddingsReady checks if embeddings service is ready
 func (h *Handler) EmbeddingsReady(w http.ResponseWriter, r *http.Request) {
 	if !h.config.Features.Embeddings {
-		h.writeError(w, http.StatusServiceUnavailable, "Embeddings service is disabled")
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service is disabled")
 		return
 	}
 
 	embeddingService := h.modelManager.GetEmbeddingService()
 	if embeddingService == nil || !embeddingService.IsReady() {
-		h.writeError(w, http.StatusServiceUnavailable, "Embeddings service is not ready")
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service is not ready")
 		return
 	}
 
@@ -152,13 +212,13 @@ func (h *Handler) EmbeddingsReady(w http.ResponseWriter, r *http.Request) {
 // EmbeddingsInfo returns embeddings service information
 func (h *Handler) EmbeddingsInfo(w http.ResponseWriter, r *http.Request) {
 	if !h.config.Features.Embeddings {
-		h.writeError(w, http.StatusServiceUnavailable, "Embeddings service is disabled")
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service is disabled")
 		return
 	}
 
 	embeddingService := h.modelManager.GetEmbeddingService()
 	if embeddingService == nil {
-		h.writeError(w, http.StatusServiceUnavailable, "Embeddings service not available")
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service not available")
 		return
 	}
 
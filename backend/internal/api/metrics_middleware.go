@@ -0,0 +1,75 @@
+package api
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"alice-backend/internal/metrics"
+
+	"github.com/gorilla/mux"
+)
+
+// MetricsMiddleware records alice_requests_total and
+// alice_request_duration_seconds for every request it wraps. It's applied
+// to the STT/TTS/embeddings subrouters in server.go's buildRouter, rather
+// than globally, so health checks and model-management calls don't dilute
+// the per-service latency histograms.
+func MetricsMiddleware(recorder *metrics.Recorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mw := &statusRecordingWriter{ResponseWriter: w, status: http.StatusOK}
+			start := time.Now()
+			next.ServeHTTP(mw, r)
+			recorder.ObserveRequest(routeTemplate(r), r.Method, mw.status, time.Since(start))
+		})
+	}
+}
+
+// routeTemplate returns the mux route's path template (e.g.
+// "/api/embeddings/collections/{name}/query"), falling back to the raw
+// path if mux hasn't matched a route - so templated path variables don't
+// each become their own alice_requests_total series.
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if tmpl, err := route.GetPathTemplate(); err == nil {
+			return tmpl
+		}
+	}
+	return r.URL.Path
+}
+
+// statusRecordingWriter captures the status code a handler wrote, since
+// http.ResponseWriter has no getter for it.
+type statusRecordingWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecordingWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Flush forwards to the wrapped writer's http.Flusher, so the STT/TTS/
+// embeddings streaming endpoints this middleware also wraps (SSE, NDJSON,
+// chunked audio) keep flushing incrementally instead of buffering until
+// ServeHTTP returns.
+func (w *statusRecordingWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack forwards to the wrapped writer's http.Hijacker, so the STT
+// WebSocket upgrade (registered on this same sttRouter) can still take over
+// the raw connection through this middleware.
+func (w *statusRecordingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("underlying ResponseWriter does not support hijacking")
+	}
+	return hj.Hijack()
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type contextKey string
+
+const (
+	modelContextKey contextKey = "alice-model"
+	voiceContextKey contextKey = "alice-voice"
+)
+
+// ModelRoutingMiddleware reads the X-Alice-Model / X-Alice-Voice headers
+// and stashes the selected model/voice variant on the request context for
+// handlers to read via modelFromContext/voiceFromContext, instead of every
+// handler parsing headers itself. A /v1/{model}/... URL segment (see
+// WithURLModel) is applied afterwards and takes precedence, since the path
+// is the more specific selector.
+func ModelRoutingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if model := r.Header.Get("X-Alice-Model"); model != "" {
+			ctx = context.WithValue(ctx, modelContextKey, model)
+		}
+		if voice := r.Header.Get("X-Alice-Voice"); voice != "" {
+			ctx = context.WithValue(ctx, voiceContextKey, voice)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// WithURLModel wraps a handler registered on a /v1/{model}/... mux route,
+// overriding the request context's resolved model with the {model} path
+// variable so it takes precedence over any X-Alice-Model header.
+func WithURLModel(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if model := mux.Vars(r)["model"]; model != "" {
+			r = r.WithContext(context.WithValue(r.Context(), modelContextKey, model))
+		}
+		next(w, r)
+	}
+}
+
+// modelFromContext returns the resolved model variant name for this
+// request, or "" to use the default backend configured at startup.
+func modelFromContext(ctx context.Context) string {
+	model, _ := ctx.Value(modelContextKey).(string)
+	return model
+}
+
+// voiceFromContext returns the resolved voice override for this request, or
+// "" if none was supplied.
+func voiceFromContext(ctx context.Context) string {
+	voice, _ := ctx.Value(voiceContextKey).(string)
+	return voice
+}
@@ -65,12 +65,14 @@ type ModelsStatusResponse struct {
 // DownloadModelRequest represents a model download request
 type DownloadModelRequest struct {
 	Service string `json:"service"`
+	Model   string `json:"model"`
 }
 
 // DownloadModelResponse represents a model download response
 type DownloadModelResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message,omitempty"`
+	JobID   string `json:"job_id,omitempty"`
 	Error   string `json:"error,omitempty"`
 }
 
@@ -81,6 +83,22 @@ type DownloadStatusResponse struct {
 	Embeddings ModelStatus `json:"embeddings"`
 }
 
+// DownloadJobStatus reports the progress of a single tracked model download,
+// as started by DownloadModel and polled via GetModelDownloadStatus.
+type DownloadJobStatus struct {
+	Status     string  `json:"status"`
+	Bytes      int64   `json:"bytes"`
+	Total      int64   `json:"total"`
+	Percent    float64 `json:"percent"`
+	ETASeconds float64 `json:"eta_seconds,omitempty"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// SelectModelRequest requests that a service switch its active model/voice.
+type SelectModelRequest struct {
+	Model string `json:"model"`
+}
+
 // HealthResponse represents the health check response
 type HealthResponse struct {
 	Status   string `json:"status"`
@@ -2,28 +2,120 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/gorilla/mux"
+
+	"alice-backend/internal/downloader"
 )
 
-// DownloadModel handles model download requests
+// DownloadModel handles model download requests. It launches a tracked
+// background job (resumable, checksum-verified) for the named model and
+// returns immediately with the job id; progress is polled via
+// GetModelDownloadStatus.
 func (h *Handler) DownloadModel(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	service := vars["service"]
 
 	var req DownloadModelRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		h.writeError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Model == "" {
+		h.writeError(w, r, http.StatusBadRequest, "model is required")
+		return
+	}
+
+	job, err := h.startModelDownload(r, service, req.Model)
+	if err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	response := DownloadModelResponse{
+	h.writeSuccess(w, DownloadModelResponse{
 		Success: true,
 		Message: "Model download started for service: " + service,
+		JobID:   job.ID,
+	})
+}
+
+func (h *Handler) startModelDownload(r *http.Request, service, model string) (*downloader.Job, error) {
+	switch service {
+	case "stt":
+		sttService := h.modelManager.GetSTTService()
+		if sttService == nil {
+			return nil, fmt.Errorf("STT service is disabled")
+		}
+		return sttService.DownloadModel(r.Context(), model)
+	case "tts":
+		ttsService := h.modelManager.GetTTSService()
+		if ttsService == nil {
+			return nil, fmt.Errorf("TTS service is disabled")
+		}
+		return ttsService.DownloadVoice(r.Context(), model)
+	case "embeddings":
+		embeddingService := h.modelManager.GetEmbeddingService()
+		if embeddingService == nil {
+			return nil, fmt.Errorf("embeddings service is disabled")
+		}
+		return embeddingService.DownloadModel(r.Context(), model)
+	default:
+		return nil, fmt.Errorf("unknown service: %s", service)
 	}
+}
 
-	h.writeSuccess(w, response)
+// SelectModel switches a service's active model/voice to one that has
+// already been downloaded, without restarting the service.
+func (h *Handler) SelectModel(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	service := vars["service"]
+
+	var req SelectModelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Model == "" {
+		h.writeError(w, r, http.StatusBadRequest, "model is required")
+		return
+	}
+
+	if err := h.selectActiveModel(r, service, req.Model); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.writeSuccess(w, DownloadModelResponse{
+		Success: true,
+		Message: "Active model for " + service + " switched to " + req.Model,
+	})
+}
+
+func (h *Handler) selectActiveModel(r *http.Request, service, model string) error {
+	switch service {
+	case "stt":
+		sttService := h.modelManager.GetSTTService()
+		if sttService == nil {
+			return fmt.Errorf("STT service is disabled")
+		}
+		return sttService.SelectModel(model)
+	case "tts":
+		ttsService := h.modelManager.GetTTSService()
+		if ttsService == nil {
+			return fmt.Errorf("TTS service is disabled")
+		}
+		return ttsService.SelectVoice(r.Context(), model)
+	case "embeddings":
+		embeddingService := h.modelManager.GetEmbeddingService()
+		if embeddingService == nil {
+			return fmt.Errorf("embeddings service is disabled")
+		}
+		return embeddingService.SelectModel(model)
+	default:
+		return fmt.Errorf("unknown service: %s", service)
+	}
 }
 
 // GetModelStatus returns the status of all models
@@ -46,8 +138,23 @@ func (h *Handler) GetModelStatus(w http.ResponseWriter, r *http.Request) {
 	h.writeSuccess(w, response)
 }
 
-// GetModelDownloadStatus returns the download status of all models
+// GetModelDownloadStatus returns the download status of all models, or, when
+// called with ?service=&model= query params, the detailed progress
+// (bytes/total/percent/ETA) of that specific tracked download job.
 func (h *Handler) GetModelDownloadStatus(w http.ResponseWriter, r *http.Request) {
+	service := r.URL.Query().Get("service")
+	model := r.URL.Query().Get("model")
+
+	if service != "" && model != "" {
+		status, err := h.modelDownloadJobStatus(service, model)
+		if err != nil {
+			h.writeError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.writeSuccess(w, status)
+		return
+	}
+
 	response := DownloadStatusResponse{
 		STT: ModelStatus{
 			Installed:   h.modelManager.GetSTTService() != nil && h.modelManager.GetSTTService().IsReady(),
@@ -65,3 +172,58 @@ func (h *Handler) GetModelDownloadStatus(w http.ResponseWriter, r *http.Request)
 
 	h.writeSuccess(w, response)
 }
+
+// RegisterModelsRoutes registers model management routes
+func (h *Handler) RegisterModelsRoutes(router *mux.Router) {
+	modelsRouter := router.PathPrefix("/api/models").Subrouter()
+	modelsRouter.HandleFunc("/download/{service}", h.DownloadModel).Methods("POST")
+	modelsRouter.HandleFunc("/status", h.GetModelStatus).Methods("GET")
+	modelsRouter.HandleFunc("/download-status", h.GetModelDownloadStatus).Methods("GET")
+	modelsRouter.HandleFunc("/download-events", h.DownloadModelEvents).Methods("GET")
+	modelsRouter.HandleFunc("/{service}/select", h.SelectModel).Methods("POST")
+}
+
+func (h *Handler) modelDownloadJobStatus(service, model string) (*DownloadJobStatus, error) {
+	var job *downloader.Job
+	var found bool
+
+	switch service {
+	case "stt":
+		sttService := h.modelManager.GetSTTService()
+		if sttService == nil {
+			return nil, fmt.Errorf("STT service is disabled")
+		}
+		job, found = sttService.ModelDownloadStatus(model)
+	case "tts":
+		ttsService := h.modelManager.GetTTSService()
+		if ttsService == nil {
+			return nil, fmt.Errorf("TTS service is disabled")
+		}
+		job, found = ttsService.VoiceDownloadStatus(model)
+	case "embeddings":
+		embeddingService := h.modelManager.GetEmbeddingService()
+		if embeddingService == nil {
+			return nil, fmt.Errorf("embeddings service is disabled")
+		}
+		job, found = embeddingService.ModelDownloadStatus(model)
+	default:
+		return nil, fmt.Errorf("unknown service: %s", service)
+	}
+
+	if !found {
+		return &DownloadJobStatus{Status: "not_found"}, nil
+	}
+
+	progress := job.Progress()
+	status := &DownloadJobStatus{
+		Status:     string(job.Status()),
+		Bytes:      progress.Bytes,
+		Total:      progress.Total,
+		Percent:    progress.Percent,
+		ETASeconds: progress.ETA.Seconds(),
+	}
+	if err := job.Err(); err != nil {
+		status.Error = err.Error()
+	}
+	return status, nil
+}
@@ -0,0 +1,236 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"alice-backend/internal/whisper"
+)
+
+// openAISegment is the per-segment detail OpenAI's verbose_json format
+// returns. AvgLogprob is approximated from the mean of the segment's
+// per-token confidences (Token.P, a linear probability) since whisper.cpp's
+// Go bindings don't surface the decoder's true average log-probability.
+type openAISegment struct {
+	Id         int     `json:"id"`
+	Start      float64 `json:"start"`
+	End        float64 `json:"end"`
+	Text       string  `json:"text"`
+	AvgLogprob float64 `json:"avg_logprob"`
+}
+
+// openAIVerboseResponse mirrors the shape of OpenAI's
+// verbose_json transcription response closely enough for drop-in clients.
+type openAIVerboseResponse struct {
+	Task     string          `json:"task"`
+	Language string          `json:"language,omitempty"`
+	Duration float64         `json:"duration"`
+	Text     string          `json:"text"`
+	Segments []openAISegment `json:"segments"`
+}
+
+// openAIJSONResponse mirrors OpenAI's default (response_format=json) shape.
+type openAIJSONResponse struct {
+	Text string `json:"text"`
+}
+
+// openAIErrorResponse mirrors OpenAI's error envelope, which is deliberately
+// not Alice's {success, error} shape so third-party clients speaking the
+// OpenAI protocol parse it the way they expect.
+type openAIErrorResponse struct {
+	Error openAIErrorBody `json:"error"`
+}
+
+type openAIErrorBody struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+func writeOpenAIError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(openAIErrorResponse{
+		Error: openAIErrorBody{Message: message, Type: "invalid_request_error"},
+	})
+}
+
+// TranscribeOpenAI implements OpenAI's POST /v1/audio/transcriptions so any
+// client that already speaks the OpenAI Whisper API protocol can use
+// Alice's local whisper as a drop-in replacement for cloud STT. Unlike the
+// rest of the api package, responses here match OpenAI's raw JSON/text
+// shape instead of Alice's {success, data} envelope.
+func (h *Handler) TranscribeOpenAI(w http.ResponseWriter, r *http.Request) {
+	if !h.config.Features.STT {
+		writeOpenAIError(w, http.StatusServiceUnavailable, "STT service is disabled")
+		return
+	}
+
+	sttService := h.modelManager.GetSTTService()
+	if sttService == nil || !sttService.IsReady() {
+		writeOpenAIError(w, http.StatusServiceUnavailable, "STT service is not ready")
+		return
+	}
+
+	if err := r.ParseMultipartForm(25 << 20); err != nil {
+		writeOpenAIError(w, http.StatusBadRequest, "Failed to parse multipart form")
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		writeOpenAIError(w, http.StatusBadRequest, "Missing required 'file' field")
+		return
+	}
+	defer file.Close()
+
+	rawAudio, err := io.ReadAll(file)
+	if err != nil {
+		writeOpenAIError(w, http.StatusInternalServerError, "Failed to read uploaded file")
+		return
+	}
+
+	prompt := r.FormValue("prompt")
+	responseFormat := r.FormValue("response_format")
+	if responseFormat == "" {
+		responseFormat = "json"
+	}
+
+	var temperature float64
+	if raw := r.FormValue("temperature"); raw != "" {
+		temperature, err = strconv.ParseFloat(raw, 64)
+		if err != nil {
+			writeOpenAIError(w, http.StatusBadRequest, "Invalid 'temperature' value")
+			return
+		}
+	}
+
+	result, err := sttService.TranscribeAudioWithDecoding(r.Context(), rawAudio, whisper.DecodingOptions{
+		InitialPrompt: prompt,
+		Temperature:   temperature,
+	})
+	if err != nil {
+		writeOpenAIError(w, http.StatusInternalServerError, "Transcription failed: "+err.Error())
+		return
+	}
+
+	switch responseFormat {
+	case "text":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(result.Text))
+
+	case "srt":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(formatSRT(result.Segments)))
+
+	case "vtt":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(formatVTT(result.Segments)))
+
+	case "verbose_json":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(openAIVerboseResponse{
+			Task:     "transcribe",
+			Language: r.FormValue("language"),
+			Duration: segmentsDuration(result.Segments),
+			Text:     result.Text,
+			Segments: toOpenAISegments(result.Segments),
+		})
+
+	case "json":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(openAIJSONResponse{Text: result.Text})
+
+	default:
+		writeOpenAIError(w, http.StatusBadRequest, "Unsupported response_format: "+responseFormat)
+	}
+}
+
+func toOpenAISegments(segments []whisper.Segment) []openAISegment {
+	out := make([]openAISegment, len(segments))
+	for i, seg := range segments {
+		out[i] = openAISegment{
+			Id:         i,
+			Start:      seg.Start.Seconds(),
+			End:        seg.End.Seconds(),
+			Text:       seg.Text,
+			AvgLogprob: avgLogprob(seg.Tokens),
+		}
+	}
+	return out
+}
+
+// avgLogprob approximates a segment's average log-probability from the mean
+// of its tokens' linear confidence, since whisper.cpp's Go bindings don't
+// expose the decoder's true average log-probability.
+func avgLogprob(tokens []whisper.Token) float64 {
+	if len(tokens) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, t := range tokens {
+		sum += float64(t.P)
+	}
+	avgP := sum / float64(len(tokens))
+	if avgP <= 0 {
+		return 0
+	}
+	return math.Log(avgP)
+}
+
+func segmentsDuration(segments []whisper.Segment) float64 {
+	if len(segments) == 0 {
+		return 0
+	}
+	return segments[len(segments)-1].End.Seconds()
+}
+
+// formatSRT renders segments as SubRip subtitles.
+func formatSRT(segments []whisper.Segment) string {
+	var b []byte
+	for i, seg := range segments {
+		b = append(b, fmt.Sprintf("%d\n%s --> %s\n%s\n\n",
+			i+1, srtTimestamp(seg.Start), srtTimestamp(seg.End), seg.Text)...)
+	}
+	return string(b)
+}
+
+// formatVTT renders segments as WebVTT subtitles.
+func formatVTT(segments []whisper.Segment) string {
+	b := []byte("WEBVTT\n\n")
+	for _, seg := range segments {
+		b = append(b, fmt.Sprintf("%s --> %s\n%s\n\n",
+			vttTimestamp(seg.Start), vttTimestamp(seg.End), seg.Text)...)
+	}
+	return string(b)
+}
+
+func srtTimestamp(d time.Duration) string {
+	ms := d.Milliseconds()
+	h := ms / 3600000
+	m := (ms % 3600000) / 60000
+	s := (ms % 60000) / 1000
+	msRem := ms % 1000
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", h, m, s, msRem)
+}
+
+func vttTimestamp(d time.Duration) string {
+	ms := d.Milliseconds()
+	h := ms / 3600000
+	m := (ms % 3600000) / 60000
+	s := (ms % 60000) / 1000
+	msRem := ms % 1000
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", h, m, s, msRem)
+}
+
+// RegisterOpenAIRoutes registers OpenAI-Whisper-API-compatible routes.
+func (h *Handler) RegisterOpenAIRoutes(router *mux.Router) {
+	v1Router := router.PathPrefix("/v1").Subrouter()
+	v1Router.HandleFunc("/audio/transcriptions", h.TranscribeOpenAI).Methods("POST")
+}
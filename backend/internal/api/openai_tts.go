@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"alice-backend/internal/audio/codec"
+	"alice-backend/internal/piper"
+)
+
+// openAISpeechRequest mirrors the body OpenAI's POST /v1/audio/speech
+// accepts. Model is accepted but ignored: Alice only ever has one TTS
+// backend configured at a time, selected via Config rather than per-request.
+type openAISpeechRequest struct {
+	Model          string  `json:"model"`
+	Input          string  `json:"input"`
+	Voice          string  `json:"voice"`
+	ResponseFormat string  `json:"response_format"`
+	Speed          float32 `json:"speed"`
+}
+
+// openAIAudioContentType returns the Content-Type the bytes of format should
+// be served with. The mapping from an OpenAI response_format to a
+// codec.Format happens in SynthesizeSpeechOpenAI, which rejects aac and pcm
+// since internal/audio/codec doesn't implement them.
+func openAIAudioContentType(format codec.Format) string {
+	switch format {
+	case codec.FormatMP3:
+		return "audio/mpeg"
+	case codec.FormatOpus:
+		return "audio/opus"
+	case codec.FormatFLAC:
+		return "audio/flac"
+	default:
+		return "audio/wav"
+	}
+}
+
+// SynthesizeSpeechOpenAI implements OpenAI's POST /v1/audio/speech so any
+// client that already speaks the OpenAI TTS API protocol can use Alice's
+// local Piper voices as a drop-in replacement for cloud TTS. Like
+// TranscribeOpenAI, the response is raw audio bytes rather than Alice's
+// {success, data} envelope.
+func (h *Handler) SynthesizeSpeechOpenAI(w http.ResponseWriter, r *http.Request) {
+	if !h.config.Features.TTS {
+		writeOpenAIError(w, http.StatusServiceUnavailable, "TTS service is disabled")
+		return
+	}
+
+	ttsService := h.modelManager.GetTTSService()
+	if ttsService == nil || !ttsService.IsReady() {
+		writeOpenAIError(w, http.StatusServiceUnavailable, "TTS service is not ready")
+		return
+	}
+
+	var req openAISpeechRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeOpenAIError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Input == "" {
+		writeOpenAIError(w, http.StatusBadRequest, "Missing required 'input' field")
+		return
+	}
+
+	responseFormat := req.ResponseFormat
+	if responseFormat == "" {
+		responseFormat = "mp3"
+	}
+
+	var format codec.Format
+	switch responseFormat {
+	case "mp3":
+		format = codec.FormatMP3
+	case "opus":
+		format = codec.FormatOpus
+	case "flac":
+		format = codec.FormatFLAC
+	case "wav":
+		format = codec.FormatWAV
+	default:
+		writeOpenAIError(w, http.StatusBadRequest, "Unsupported response_format: "+responseFormat)
+		return
+	}
+
+	voice := req.Voice
+	if voice == "" {
+		voice = ttsService.GetDefaultVoice()
+	}
+
+	w.Header().Set("Content-Type", openAIAudioContentType(format))
+	opts := piper.SynthesizeOptions{Format: format, Speed: req.Speed}
+	if err := ttsService.SynthesizeToWriter(r.Context(), req.Input, voice, opts, w); err != nil {
+		writeOpenAIError(w, http.StatusInternalServerError, "Speech synthesis failed: "+err.Error())
+		return
+	}
+}
+
+// RegisterOpenAITTSRoutes registers OpenAI-TTS-API-compatible routes.
+func (h *Handler) RegisterOpenAITTSRoutes(router *mux.Router) {
+	v1Router := router.PathPrefix("/v1").Subrouter()
+	v1Router.HandleFunc("/audio/speech", h.SynthesizeSpeechOpenAI).Methods("POST")
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+const requestIDContextKey contextKey = "alice-request-id"
+
+// requestIDHeader is the header clients may set to propagate their own
+// request id, and that's always echoed back on the response.
+const requestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware assigns a request id (or keeps the caller-supplied
+// X-Request-ID), stashing it on the request context so writeError can
+// include it in the JSON error envelope, and echoes it on the response so
+// a client can correlate a failure with backend logs.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if id == "" {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+		ctx := context.WithValue(r.Context(), requestIDContextKey, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestIDFromContext returns the request id assigned by
+// RequestIDMiddleware, or "" if none was assigned (e.g. in code paths that
+// don't run through it).
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey).(string)
+	return id
+}
+
+func newRequestID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b[:])
+}
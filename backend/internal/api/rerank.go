@@ -0,0 +1,65 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"alice-backend/internal/minilm"
+)
+
+// RerankRequest mirrors the LocalAI/Cohere rerank request shape.
+type RerankRequest struct {
+	Query           string                `json:"query"`
+	Documents       []string              `json:"documents"`
+	TopK            int                   `json:"top_k,omitempty"`
+	ReturnDocuments bool                  `json:"return_documents,omitempty"`
+	Strategy        minilm.RerankStrategy `json:"strategy,omitempty"`
+}
+
+// RerankResponse wraps the ranked results.
+type RerankResponse struct {
+	Results []minilm.RerankResult `json:"results"`
+}
+
+// Rerank handles POST /api/rerank
+func (h *Handler) Rerank(w http.ResponseWriter, r *http.Request) {
+	if !h.config.Features.Embeddings {
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service is disabled")
+		return
+	}
+
+	rerankService := h.modelManager.GetRerankService()
+	if rerankService == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service is not ready")
+		return
+	}
+
+	var req RerankRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Query == "" {
+		h.writeError(w, r, http.StatusBadRequest, "Query is required")
+		return
+	}
+	if len(req.Documents) == 0 {
+		h.writeError(w, r, http.StatusBadRequest, "Documents are required")
+		return
+	}
+
+	results, err := rerankService.Rerank(r.Context(), req.Query, req.Documents, req.TopK, req.Strategy)
+	if err != nil {
+		h.writeError(w, r, http.StatusInternalServerError, "Rerank failed: "+err.Error())
+		return
+	}
+
+	if !req.ReturnDocuments {
+		for i := range results {
+			results[i].Document = ""
+		}
+	}
+
+	h.writeSuccess(w, RerankResponse{Results: results})
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -25,18 +26,27 @@ type TranscribeResponse struct {
 // TranscribeAudio handles audio transcription (supports both multipart and JSON)
 func (h *Handler) TranscribeAudio(w http.ResponseWriter, r *http.Request) {
 	if !h.config.Features.STT {
-		h.writeError(w, http.StatusServiceUnavailable, "STT service is disabled")
+		h.writeError(w, r, http.StatusServiceUnavailable, "STT service is disabled")
 		return
 	}
 
-	sttService := h.modelManager.GetSTTService()
+	sttService, err := h.modelManager.GetSTTServiceFor(r.Context(), modelFromContext(r.Context()))
+	if err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
 	if sttService == nil || !sttService.IsReady() {
-		h.writeError(w, http.StatusServiceUnavailable, "STT service is not ready")
+		h.writeError(w, r, http.StatusServiceUnavailable, "STT service is not ready")
+		return
+	}
+
+	monitor := h.modelManager.GetMonitor()
+	if !monitor.Allow("stt") {
+		h.writeError(w, r, http.StatusServiceUnavailable, "STT service is recovering from an error spike, try again shortly")
 		return
 	}
 
 	var audioData []byte
-	var err error
 
 	// Check Content-Type to determine request format
 	contentType := r.Header.Get("Content-Type")
@@ -45,12 +55,12 @@ func (h *Handler) TranscribeAudio(w http.ResponseWriter, r *http.Request) {
 		// Handle JSON request (from frontend audio processing)
 		var req TranscribeRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			h.writeError(w, http.StatusBadRequest, "Invalid JSON request body")
+			h.writeError(w, r, http.StatusBadRequest, "Invalid JSON request body")
 			return
 		}
 
 		if len(req.AudioData) == 0 {
-			h.writeError(w, http.StatusBadRequest, "Audio data is required")
+			h.writeError(w, r, http.StatusBadRequest, "Audio data is required")
 			return
 		}
 
@@ -74,7 +84,7 @@ func (h *Handler) TranscribeAudio(w http.ResponseWriter, r *http.Request) {
 	} else {
 		// Handle multipart form (file upload)
 		if err := r.ParseMultipartForm(10 << 20); err != nil {
-			h.writeError(w, http.StatusBadRequest, "Failed to parse multipart form")
+			h.writeError(w, r, http.StatusBadRequest, "Failed to parse multipart form")
 			return
 		}
 
@@ -83,7 +93,7 @@ func (h *Handler) TranscribeAudio(w http.ResponseWriter, r *http.Request) {
 			// Try "audio" field for backward compatibility
 			file, _, err = r.FormFile("audio")
 			if err != nil {
-				h.writeError(w, http.StatusBadRequest, "Failed to get audio file (expected 'file' or 'audio' field)")
+				h.writeError(w, r, http.StatusBadRequest, "Failed to get audio file (expected 'file' or 'audio' field)")
 				return
 			}
 		}
@@ -92,15 +102,19 @@ func (h *Handler) TranscribeAudio(w http.ResponseWriter, r *http.Request) {
 		// Read audio data
 		audioData, err = io.ReadAll(file)
 		if err != nil {
-			h.writeError(w, http.StatusInternalServerError, "Failed to read audio file")
+			h.writeError(w, r, http.StatusInternalServerError, "Failed to read audio file")
 			return
 		}
 	}
 
 	// Transcribe audio
+	start := time.Now()
 	text, err := sttService.TranscribeAudio(r.Context(), audioData)
+	duration := time.Since(start)
+	monitor.RecordResult("stt", duration, err)
+	h.modelManager.GetMetricsRecorder().ObserveModelInference("stt", duration)
 	if err != nil {
-		h.writeError(w, http.StatusInternalServerError, "Transcription failed: "+err.Error())
+		h.writeError(w, r, http.StatusInternalServerError, "Transcription failed: "+err.Error())
 		return
 	}
 
@@ -115,4 +129,5 @@ func (h *Handler) TranscribeAudio(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) RegisterSTTRoutes(router *mux.Router) {
 	sttRouter := router.PathPrefix("/api/stt").Subrouter()
 	sttRouter.HandleFunc("/transcribe", h.TranscribeAudio).Methods("POST")
+	sttRouter.HandleFunc("/stream", h.TranscribeStreamWS)
 }
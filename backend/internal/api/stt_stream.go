@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+
+	"alice-backend/internal/whisper"
+)
+
+// streamMessage is the wire format for one WebSocket transcription update:
+// {type:"partial"|"final", text, t_start, t_end, confidence}, mirroring the
+// shape used by other realtime STT streaming APIs so the desktop client
+// doesn't need an Alice-specific parser.
+type streamMessage struct {
+	Type       string  `json:"type"`
+	Text       string  `json:"text"`
+	TStart     float64 `json:"t_start"`
+	TEnd       float64 `json:"t_end"`
+	Confidence float32 `json:"confidence"`
+}
+
+// toStreamMessage converts whisper's internal PartialResult into the wire
+// format above. Confidence is a placeholder: transcribeDirectly's binary
+// backend only returns flattened text, not per-token log-probabilities, so
+// there's no real per-segment confidence to report here yet.
+func toStreamMessage(r whisper.PartialResult) streamMessage {
+	msgType := "partial"
+	if r.IsFinal {
+		msgType = "final"
+	}
+	return streamMessage{
+		Type:       msgType,
+		Text:       r.Text,
+		TStart:     float64(r.StartMs) / 1000.0,
+		TEnd:       float64(r.EndMs) / 1000.0,
+		Confidence: 0.95,
+	}
+}
+
+var sttStreamUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	// Origin is already enforced by corsMiddleware upstream of the router.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// TranscribeStreamWS upgrades the connection to a WebSocket and streams
+// partial/final transcripts for a live PCM feed. Clients send raw
+// little-endian 16-bit PCM frames (mono); the server replies with one
+// JSON-encoded streamMessage (see toStreamMessage) per PartialResult.
+func (h *Handler) TranscribeStreamWS(w http.ResponseWriter, r *http.Request) {
+	if !h.config.Features.STT {
+		h.writeError(w, r, http.StatusServiceUnavailable, "STT service is disabled")
+		return
+	}
+
+	sttService := h.modelManager.GetSTTService()
+	if sttService == nil || !sttService.IsReady() {
+		h.writeError(w, r, http.StatusServiceUnavailable, "STT service is not ready")
+		return
+	}
+
+	conn, err := sttStreamUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("STT stream upgrade failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	pcm := make(chan []float32)
+	results, err := sttService.TranscribeStream(ctx, pcm)
+	if err != nil {
+		conn.WriteJSON(map[string]string{"error": err.Error()})
+		return
+	}
+
+	go func() {
+		defer close(pcm)
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			select {
+			case pcm <- pcmBytesToSamples(data):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for result := range results {
+		if err := conn.WriteJSON(toStreamMessage(result)); err != nil {
+			return
+		}
+	}
+}
+
+// pcmBytesToSamples converts little-endian 16-bit PCM bytes to float32
+// samples in [-1, 1], mirroring STTService.convertAudioToSamples.
+func pcmBytesToSamples(data []byte) []float32 {
+	n := len(data) / 2
+	samples := make([]float32, n)
+	for i := 0; i < n; i++ {
+		sample := int16(data[i*2]) | int16(data[i*2+1])<<8
+		samples[i] = float32(sample) / 32768.0
+	}
+	return samples
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"alice-backend/internal/apierr"
+	"alice-backend/internal/config"
+)
+
+// requestTimeoutHeader lets a caller bound how long the server works on
+// their request, overriding the per-service default TimeoutMiddleware would
+// otherwise apply.
+const requestTimeoutHeader = "X-Request-Timeout"
+
+// TimeoutMiddleware wraps each request's context with a deadline - the
+// duration in X-Request-Timeout if the caller set one and it parses,
+// otherwise the per-service default from cfg.Features matching the
+// request's path - so a slow STT/TTS/embedding call (and the ONNX/whisper
+// session driving it) is cancelled at its next checkpoint instead of
+// running on after the caller has stopped waiting. A request that hits its
+// deadline gets a 504 in the same JSON envelope writeAPIError uses.
+func TimeoutMiddleware(cfg *config.Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout := defaultTimeoutFor(cfg, r.URL.Path)
+			if raw := r.Header.Get(requestTimeoutHeader); raw != "" {
+				if d, err := parseTimeoutHeader(raw); err == nil && d > 0 {
+					timeout = d
+				}
+			}
+			if timeout <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithCancel(r.Context())
+			defer cancel()
+
+			dt := newDeadlineTimer()
+			expired := dt.Reset(timeout)
+			defer dt.Stop()
+
+			tw := &timeoutWriter{ResponseWriter: w}
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				next.ServeHTTP(tw, r.WithContext(ctx))
+			}()
+
+			select {
+			case <-done:
+			case <-expired:
+				// Cancel the handler's context so an in-flight ONNX/whisper
+				// session or HTTP call to a cloud TTS provider unwinds at its
+				// next context check instead of running to completion
+				// unobserved.
+				cancel()
+				tw.markTimedOut()
+				if !tw.headerWritten() {
+					writeTimeoutResponse(w, r)
+				}
+				<-done
+			}
+		})
+	}
+}
+
+// parseTimeoutHeader accepts either a Go duration string ("5s") or a bare
+// number of seconds ("5"), since most HTTP clients find a plain number
+// easier to set than a duration literal.
+func parseTimeoutHeader(raw string) (time.Duration, error) {
+	if secs, err := strconv.ParseFloat(raw, 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), nil
+	}
+	return time.ParseDuration(raw)
+}
+
+// defaultTimeoutFor picks the configured per-service timeout matching the
+// request path's prefix, falling back to cfg.Features.RequestTimeout.
+func defaultTimeoutFor(cfg *config.Config, path string) time.Duration {
+	switch {
+	case strings.HasPrefix(path, "/api/stt"):
+		if cfg.Features.STTTimeout > 0 {
+			return cfg.Features.STTTimeout
+		}
+	case strings.HasPrefix(path, "/api/tts"):
+		if cfg.Features.TTSTimeout > 0 {
+			return cfg.Features.TTSTimeout
+		}
+	case strings.HasPrefix(path, "/api/embeddings"), strings.HasPrefix(path, "/api/rerank"):
+		if cfg.Features.EmbeddingsTimeout > 0 {
+			return cfg.Features.EmbeddingsTimeout
+		}
+	}
+	return cfg.Features.RequestTimeout
+}
+
+// writeTimeoutResponse writes the same {"success":false,"error":{...}} JSON
+// envelope writeAPIError builds; it's duplicated here rather than called
+// through Handler because this middleware runs ahead of any particular
+// Handler method and only has the apierr/RequestIDFromContext pieces to
+// work with (the same tradeoff server.go's recoveryMiddleware makes).
+func writeTimeoutResponse(w http.ResponseWriter, r *http.Request) {
+	apiErr := apierr.New(apierr.ErrTimeout, http.StatusGatewayTimeout, "Request exceeded its deadline")
+	requestID := RequestIDFromContext(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(apiErr.HTTPStatus)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": false,
+		"error": map[string]interface{}{
+			"code":       apiErr.Code,
+			"message":    apiErr.Message,
+			"request_id": requestID,
+		},
+	})
+}
+
+// timeoutWriter lets TimeoutMiddleware tell whether the wrapped handler
+// already started writing a response before the deadline fired, and
+// suppresses further writes once the middleware has written its own 504 so
+// the two responses can't interleave on the wire.
+type timeoutWriter struct {
+	http.ResponseWriter
+
+	mu          sync.Mutex
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) headerWritten() bool {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return tw.wroteHeader
+}
+
+func (tw *timeoutWriter) markTimedOut() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.timedOut = true
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+	tw.ResponseWriter.WriteHeader(code)
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	if tw.timedOut {
+		tw.mu.Unlock()
+		return 0, http.ErrHandlerTimeout
+	}
+	wroteHeader := tw.wroteHeader
+	tw.wroteHeader = true
+	tw.mu.Unlock()
+
+	if !wroteHeader {
+		tw.ResponseWriter.WriteHeader(http.StatusOK)
+	}
+	return tw.ResponseWriter.Write(b)
+}
+
+// Flush forwards to the wrapped writer's http.Flusher, so a streaming
+// handler (SSE, NDJSON, chunked audio) running under TimeoutMiddleware still
+// flushes incrementally instead of buffering until ServeHTTP returns.
+func (tw *timeoutWriter) Flush() {
+	if f, ok := tw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack forwards to the wrapped writer's http.Hijacker, so a WebSocket
+// upgrade running under TimeoutMiddleware can still take over the raw
+// connection. Once hijacked, the caller owns the connection directly -
+// TimeoutMiddleware's own deadline/cancellation no longer applies to it.
+func (tw *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := tw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("underlying ResponseWriter does not support hijacking")
+	}
+	return hj.Hijack()
+}
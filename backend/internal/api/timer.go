@@ -0,0 +1,60 @@
+package api
+
+import (
+	"sync"
+	"time"
+)
+
+// deadlineTimer wraps a time.Timer so a single instance can be safely
+// stopped and re-armed for reuse - the same trick gVisor's gonet package
+// uses for net.Conn's SetDeadline. time.Timer.Stop returning false only
+// means the timer had already fired; it says nothing about whether that
+// fire has been received from the channel yet, so resetting a fired timer
+// back onto the same channel can let a stale fire close a later deadline's
+// channel out from under it. Handing out a fresh channel on every Reset
+// instead means a late, already-in-flight fire finds its channel is no
+// longer the current one and leaves it unclosed.
+type deadlineTimer struct {
+	mu    sync.Mutex
+	timer *time.Timer
+	ch    chan struct{}
+}
+
+// newDeadlineTimer returns an unarmed deadlineTimer; call Reset to start it.
+func newDeadlineTimer() *deadlineTimer {
+	return &deadlineTimer{}
+}
+
+// Reset (re)arms the timer to fire after duration, returning the channel
+// that will be closed when it does. Safe to call again before the previous
+// deadline fires.
+func (d *deadlineTimer) Reset(duration time.Duration) <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+
+	ch := make(chan struct{})
+	d.ch = ch
+	d.timer = time.AfterFunc(duration, func() {
+		d.mu.Lock()
+		current := d.ch == ch
+		d.mu.Unlock()
+		if current {
+			close(ch)
+		}
+	})
+	return ch
+}
+
+// Stop disarms the timer. Safe to call even if it already fired or Reset
+// was never called.
+func (d *deadlineTimer) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+}
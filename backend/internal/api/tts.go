@@ -3,15 +3,21 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
+
+	"alice-backend/internal/audio/codec"
+	"alice-backend/internal/piper"
 )
 
 // SynthesizeRequest represents a TTS synthesis request
 type SynthesizeRequest struct {
-	Text  string  `json:"text"`
-	Voice string  `json:"voice,omitempty"`
-	Speed float32 `json:"speed,omitempty"`
+	Text   string       `json:"text"`
+	Voice  string       `json:"voice,omitempty"`
+	Speed  float32      `json:"speed,omitempty"`
+	Format codec.Format `json:"format,omitempty"`
 }
 
 // VoiceResponse represents a voice information response
@@ -25,34 +31,69 @@ type VoiceResponse struct {
 // SynthesizeSpeech handles TTS synthesis
 func (h *Handler) SynthesizeSpeech(w http.ResponseWriter, r *http.Request) {
 	if !h.config.Features.TTS {
-		h.writeError(w, http.StatusServiceUnavailable, "TTS service is disabled")
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service is disabled")
 		return
 	}
 
 	ttsService := h.modelManager.GetTTSService()
 	if ttsService == nil || !ttsService.IsReady() {
-		h.writeError(w, http.StatusServiceUnavailable, "TTS service is not ready")
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service is not ready")
+		return
+	}
+
+	monitor := h.modelManager.GetMonitor()
+	if !monitor.Allow("tts") {
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service is recovering from an error spike, try again shortly")
 		return
 	}
 
 	var req SynthesizeRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		h.writeError(w, r, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	if req.Text == "" {
-		h.writeError(w, http.StatusBadRequest, "Text is required")
+		h.writeError(w, r, http.StatusBadRequest, "Text is required")
 		return
 	}
 
+	if req.Voice == "" {
+		req.Voice = voiceFromContext(r.Context())
+	}
 	if req.Voice == "" {
 		req.Voice = "en-US-amy-medium"
 	}
 
-	audioData, err := ttsService.Synthesize(r.Context(), req.Text, req.Voice)
+	format := req.Format
+	if format == "" {
+		format = codec.FormatWAV
+	}
+
+	recorder := h.modelManager.GetMetricsRecorder()
+
+	// Clients that ask for audio/* get the raw bytes straight off the wire
+	// instead of paying to have every byte boxed into a JSON int array.
+	if acceptsAudio(r) {
+		w.Header().Set("Content-Type", contentTypeForFormat(format))
+		start := time.Now()
+		err := ttsService.SynthesizeToWriter(r.Context(), req.Text, req.Voice, piper.SynthesizeOptions{Format: format, Speed: req.Speed}, w)
+		duration := time.Since(start)
+		monitor.RecordResult("tts", duration, err)
+		recorder.ObserveModelInference("tts", duration)
+		if err != nil {
+			h.writeError(w, r, http.StatusInternalServerError, "TTS synthesis failed: "+err.Error())
+		}
+		return
+	}
+
+	start := time.Now()
+	audioData, err := ttsService.Synthesize(r.Context(), req.Text, req.Voice, format)
+	duration := time.Since(start)
+	monitor.RecordResult("tts", duration, err)
+	recorder.ObserveModelInference("tts", duration)
 	if err != nil {
-		h.writeError(w, http.StatusInternalServerError, "TTS synthesis failed: "+err.Error())
+		h.writeError(w, r, http.StatusInternalServerError, "TTS synthesis failed: "+err.Error())
 		return
 	}
 
@@ -64,7 +105,7 @@ func (h *Handler) SynthesizeSpeech(w http.ResponseWriter, r *http.Request) {
 
 	response := map[string]interface{}{
 		"audio":       audioNumbers,
-		"format":      "wav",
+		"format":      string(format),
 		"sample_rate": 22050,
 		"duration":    1.0, // Placeholder duration
 	}
@@ -72,16 +113,23 @@ func (h *Handler) SynthesizeSpeech(w http.ResponseWriter, r *http.Request) {
 	h.writeSuccess(w, response)
 }
 
+// acceptsAudio reports whether the request's Accept header names an audio/*
+// media type, the signal SynthesizeSpeech uses to skip the JSON int-array
+// envelope and write raw audio bytes instead.
+func acceptsAudio(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "audio/")
+}
+
 // GetVoices returns available TTS voices
 func (h *Handler) GetVoices(w http.ResponseWriter, r *http.Request) {
 	if !h.config.Features.TTS {
-		h.writeError(w, http.StatusServiceUnavailable, "TTS service is disabled")
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service is disabled")
 		return
 	}
 
 	ttsService := h.modelManager.GetTTSService()
 	if ttsService == nil {
-		h.writeError(w, http.StatusServiceUnavailable, "TTS service not available")
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service not available")
 		return
 	}
 
@@ -113,13 +161,13 @@ type SetDefaultVoiceRequest struct {
 // GetDefaultVoice returns the current default voice
 func (h *Handler) GetDefaultVoice(w http.ResponseWriter, r *http.Request) {
 	if !h.config.Features.TTS {
-		h.writeError(w, http.StatusServiceUnavailable, "TTS service is disabled")
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service is disabled")
 		return
 	}
 
 	ttsService := h.modelManager.GetTTSService()
 	if ttsService == nil {
-		h.writeError(w, http.StatusServiceUnavailable, "TTS service not available")
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service not available")
 		return
 	}
 
@@ -134,29 +182,29 @@ func (h *Handler) GetDefaultVoice(w http.ResponseWriter, r *http.Request) {
 // SetDefaultVoice sets the default voice for TTS
 func (h *Handler) SetDefaultVoice(w http.ResponseWriter, r *http.Request) {
 	if !h.config.Features.TTS {
-		h.writeError(w, http.StatusServiceUnavailable, "TTS service is disabled")
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service is disabled")
 		return
 	}
 
 	ttsService := h.modelManager.GetTTSService()
 	if ttsService == nil || !ttsService.IsReady() {
-		h.writeError(w, http.StatusServiceUnavailable, "TTS service is not ready")
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service is not ready")
 		return
 	}
 
 	var req SetDefaultVoiceRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		h.writeError(w, r, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	if req.Voice == "" {
-		h.writeError(w, http.StatusBadRequest, "Voice is required")
+		h.writeError(w, r, http.StatusBadRequest, "Voice is required")
 		return
 	}
 
 	if err := ttsService.SetDefaultVoice(req.Voice); err != nil {
-		h.writeError(w, http.StatusBadRequest, err.Error())
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -168,11 +216,174 @@ func (h *Handler) SetDefaultVoice(w http.ResponseWriter, r *http.Request) {
 	h.writeSuccess(w, response)
 }
 
+// CatalogVoiceResponse represents one voice entry from the upstream voice
+// catalog, including the download metadata VoiceResponse doesn't carry.
+type CatalogVoiceResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Language    string `json:"language"`
+	Speaker     string `json:"speaker,omitempty"`
+	Quality     string `json:"quality"`
+	SampleRate  int    `json:"sample_rate"`
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256,omitempty"`
+	DownloadURL string `json:"download_url"`
+}
+
+// ListCatalogVoices returns every voice published in the upstream voice
+// catalog, optionally filtered by language code (e.g. ?lang=de_DE), so the UI
+// can browse and install voices beyond the handful already downloaded.
+func (h *Handler) ListCatalogVoices(w http.ResponseWriter, r *http.Request) {
+	if !h.config.Features.TTS {
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service is disabled")
+		return
+	}
+
+	ttsService := h.modelManager.GetTTSService()
+	if ttsService == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service not available")
+		return
+	}
+
+	lang := r.URL.Query().Get("lang")
+	voices, err := ttsService.ListCatalogVoices(r.Context(), lang)
+	if err != nil {
+		h.writeError(w, r, http.StatusBadGateway, "failed to load voice catalog: "+err.Error())
+		return
+	}
+
+	voiceResponses := make([]CatalogVoiceResponse, len(voices))
+	for i, voice := range voices {
+		voiceResponses[i] = CatalogVoiceResponse{
+			Name:        voice.Name,
+			Description: voice.Description,
+			Language:    voice.Language,
+			Speaker:     voice.Speaker,
+			Quality:     voice.Quality,
+			SampleRate:  voice.SampleRate,
+			Size:        voice.Size,
+			SHA256:      voice.SHA256,
+			DownloadURL: voice.DownloadURL,
+		}
+	}
+
+	h.writeSuccess(w, map[string]interface{}{
+		"voices": voiceResponses,
+	})
+}
+
+// InstallVoiceRequest represents a request to install a catalog voice
+type InstallVoiceRequest struct {
+	Voice string `json:"voice"`
+}
+
+// InstallVoice downloads and verifies a voice from the catalog, blocking
+// until it's ready to use rather than returning a job id to poll.
+func (h *Handler) InstallVoice(w http.ResponseWriter, r *http.Request) {
+	if !h.config.Features.TTS {
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service is disabled")
+		return
+	}
+
+	ttsService := h.modelManager.GetTTSService()
+	if ttsService == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service not available")
+		return
+	}
+
+	var req InstallVoiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Voice == "" {
+		h.writeError(w, r, http.StatusBadRequest, "voice is required")
+		return
+	}
+
+	if err := ttsService.InstallVoice(r.Context(), req.Voice); err != nil {
+		h.writeError(w, r, http.StatusBadGateway, "failed to install voice: "+err.Error())
+		return
+	}
+
+	h.writeSuccess(w, map[string]interface{}{
+		"message": "Voice installed successfully",
+		"voice":   req.Voice,
+	})
+}
+
+// InstallVoiceByID is the path-parameter counterpart to InstallVoice, for
+// callers that'd rather POST /voices/install/{id} than send a JSON body.
+func (h *Handler) InstallVoiceByID(w http.ResponseWriter, r *http.Request) {
+	if !h.config.Features.TTS {
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service is disabled")
+		return
+	}
+
+	ttsService := h.modelManager.GetTTSService()
+	if ttsService == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service not available")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		h.writeError(w, r, http.StatusBadRequest, "voice id is required")
+		return
+	}
+
+	if err := ttsService.InstallVoice(r.Context(), id); err != nil {
+		h.writeError(w, r, http.StatusBadGateway, "failed to install voice: "+err.Error())
+		return
+	}
+
+	h.writeSuccess(w, map[string]interface{}{
+		"message": "Voice installed successfully",
+		"voice":   id,
+	})
+}
+
+// RemoveVoice uninstalls a previously installed voice, deleting its model
+// and config from disk.
+func (h *Handler) RemoveVoice(w http.ResponseWriter, r *http.Request) {
+	if !h.config.Features.TTS {
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service is disabled")
+		return
+	}
+
+	ttsService := h.modelManager.GetTTSService()
+	if ttsService == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service not available")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		h.writeError(w, r, http.StatusBadRequest, "voice id is required")
+		return
+	}
+
+	if err := ttsService.RemoveVoice(id); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.writeSuccess(w, map[string]interface{}{
+		"message": "Voice removed successfully",
+		"voice":   id,
+	})
+}
+
 // RegisterTTSRoutes registers TTS-related routes
 func (h *Handler) RegisterTTSRoutes(router *mux.Router) {
 	ttsRouter := router.PathPrefix("/api/tts").Subrouter()
 	ttsRouter.HandleFunc("/synthesize", h.SynthesizeSpeech).Methods("POST")
 	ttsRouter.HandleFunc("/voices", h.GetVoices).Methods("GET")
+	ttsRouter.HandleFunc("/voices/available", h.ListCatalogVoices).Methods("GET")
 	ttsRouter.HandleFunc("/default-voice", h.GetDefaultVoice).Methods("GET")
 	ttsRouter.HandleFunc("/default-voice", h.SetDefaultVoice).Methods("POST")
+	ttsRouter.HandleFunc("/stream", h.SynthesizeSpeechStream).Methods("POST")
+	ttsRouter.HandleFunc("/voices/install", h.InstallVoice).Methods("POST")
+	ttsRouter.HandleFunc("/voices/install/{id}", h.InstallVoiceByID).Methods("POST")
+	ttsRouter.HandleFunc("/voices/{id}", h.RemoveVoice).Methods("DELETE")
 }
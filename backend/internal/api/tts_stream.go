@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"alice-backend/internal/audio/codec"
+)
+
+// SynthesizeSpeechStream streams TTS audio back as each sentence finishes
+// synthesizing, rather than waiting for the entire input to be spoken.
+// The response is a single audio body (audio/wav unless the request picks
+// another Format, see contentTypeForFormat) delivered with chunked transfer
+// encoding: the client can start playback as soon as the first chunk
+// arrives instead of waiting out the full synthesis latency.
+func (h *Handler) SynthesizeSpeechStream(w http.ResponseWriter, r *http.Request) {
+	if !h.config.Features.TTS {
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service is disabled")
+		return
+	}
+
+	ttsService := h.modelManager.GetTTSService()
+	if ttsService == nil || !ttsService.IsReady() {
+		h.writeError(w, r, http.StatusServiceUnavailable, "TTS service is not ready")
+		return
+	}
+
+	var req SynthesizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Text == "" {
+		h.writeError(w, r, http.StatusBadRequest, "Text is required")
+		return
+	}
+
+	if req.Voice == "" {
+		req.Voice = "en-US-amy-medium"
+	}
+
+	format := req.Format
+	if format == "" {
+		format = codec.FormatWAV
+	}
+
+	chunks, err := ttsService.SynthesizeStream(r.Context(), req.Text, req.Voice, format)
+	if err != nil {
+		h.writeError(w, r, http.StatusInternalServerError, "TTS synthesis failed: "+err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeForFormat(format))
+	w.WriteHeader(http.StatusOK)
+
+	flusher, canFlush := w.(http.Flusher)
+	for chunk := range chunks {
+		if chunk.Err != nil {
+			log.Printf("TTS stream synthesis failed mid-stream: %v", chunk.Err)
+			return
+		}
+		if _, err := w.Write(chunk.Data); err != nil {
+			return
+		}
+		if canFlush {
+			flusher.Flush()
+		}
+	}
+}
+
+// contentTypeForFormat maps a codec.Format to the MIME type clients should
+// expect on the response body.
+func contentTypeForFormat(format codec.Format) string {
+	switch format {
+	case codec.FormatOpus:
+		return "audio/ogg"
+	case codec.FormatMP3:
+		return "audio/mpeg"
+	case codec.FormatFLAC:
+		return "audio/flac"
+	default:
+		return "audio/wav"
+	}
+}
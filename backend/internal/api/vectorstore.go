@@ -0,0 +1,229 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"alice-backend/internal/minilm"
+)
+
+// UpsertVectorRequest represents one record to store in a vector store
+// collection. Embedding is optional: when omitted, the server embeds Text
+// (serialized together with Metadata via minilm.SerializeForEmbedding) using
+// the active embeddings service.
+type UpsertVectorRequest struct {
+	ID        string            `json:"id"`
+	Text      string            `json:"text,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Embedding []float32         `json:"embedding,omitempty"`
+}
+
+// VectorRecordResponse is a stored record as returned by query results.
+type VectorRecordResponse struct {
+	ID       string            `json:"id"`
+	Text     string            `json:"text"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Score    float32           `json:"score"`
+}
+
+// QueryVectorRequest searches a collection by either a precomputed Embedding
+// or, when Embedding is omitted, by Text embedded server-side the same way
+// UpsertVectorRequest embeds it.
+type QueryVectorRequest struct {
+	Embedding []float32         `json:"embedding,omitempty"`
+	Text      string            `json:"text,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	TopK      int               `json:"top_k,omitempty"`
+}
+
+// embeddingServiceForVectors returns the embeddings service used to embed
+// text server-side for vector store requests that don't supply a
+// precomputed embedding, writing the standard error response if it's
+// unavailable.
+func (h *Handler) embeddingServiceForVectors(w http.ResponseWriter, r *http.Request) *minilm.EmbeddingService {
+	if !h.config.Features.Embeddings {
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service is disabled")
+		return nil
+	}
+
+	embeddingService := h.modelManager.GetEmbeddingService()
+	if embeddingService == nil || !embeddingService.IsReady() {
+		h.writeError(w, r, http.StatusServiceUnavailable, "Embeddings service is not ready")
+		return nil
+	}
+	return embeddingService
+}
+
+// vectorStoreFor returns the Manager's persistent vector store, writing the
+// standard error response if it isn't available.
+func (h *Handler) vectorStoreFor(w http.ResponseWriter, r *http.Request) *minilm.VectorStore {
+	store := h.modelManager.GetVectorStore()
+	if store == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "Vector store is not available")
+		return nil
+	}
+	return store
+}
+
+// UpsertVector handles POST /api/embeddings/collections/{name}/upsert,
+// storing (and indexing) one record in the named collection.
+func (h *Handler) UpsertVector(w http.ResponseWriter, r *http.Request) {
+	collection := mux.Vars(r)["name"]
+
+	store := h.vectorStoreFor(w, r)
+	if store == nil {
+		return
+	}
+
+	var req UpsertVectorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.ID == "" {
+		h.writeError(w, r, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	embedding := req.Embedding
+	if len(embedding) == 0 {
+		embeddingService := h.embeddingServiceForVectors(w, r)
+		if embeddingService == nil {
+			return
+		}
+		text := minilm.SerializeForEmbedding(req.Text, req.Metadata)
+		if text == "" {
+			h.writeError(w, r, http.StatusBadRequest, "text, metadata, or embedding is required")
+			return
+		}
+		var err error
+		embedding, err = embeddingService.GenerateEmbedding(r.Context(), text)
+		if err != nil {
+			h.writeError(w, r, http.StatusInternalServerError, "Embedding generation failed: "+err.Error())
+			return
+		}
+	}
+
+	record := minilm.Record{ID: req.ID, Text: req.Text, Metadata: req.Metadata, Embedding: embedding}
+	if err := store.Upsert(collection, record); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "Upsert failed: "+err.Error())
+		return
+	}
+
+	h.writeSuccess(w, map[string]interface{}{
+		"message": "Record upserted successfully",
+		"id":      req.ID,
+	})
+}
+
+// QueryVectorCollection handles POST /api/embeddings/collections/{name}/query,
+// returning the topK records nearest the request's embedding (or, if none is
+// supplied, nearest the server-side embedding of its text/metadata).
+func (h *Handler) QueryVectorCollection(w http.ResponseWriter, r *http.Request) {
+	collection := mux.Vars(r)["name"]
+
+	store := h.vectorStoreFor(w, r)
+	if store == nil {
+		return
+	}
+
+	var req QueryVectorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	queryEmbedding := req.Embedding
+	if len(queryEmbedding) == 0 {
+		embeddingService := h.embeddingServiceForVectors(w, r)
+		if embeddingService == nil {
+			return
+		}
+		text := minilm.SerializeForEmbedding(req.Text, req.Metadata)
+		if text == "" {
+			h.writeError(w, r, http.StatusBadRequest, "text, metadata, or embedding is required")
+			return
+		}
+		var err error
+		queryEmbedding, err = embeddingService.GenerateEmbedding(r.Context(), text)
+		if err != nil {
+			h.writeError(w, r, http.StatusInternalServerError, "Embedding generation failed: "+err.Error())
+			return
+		}
+	}
+
+	topK := req.TopK
+	if topK <= 0 {
+		topK = 5
+	}
+
+	records, scores, err := store.Query(collection, queryEmbedding, topK)
+	if err != nil {
+		h.writeError(w, r, http.StatusInternalServerError, "Query failed: "+err.Error())
+		return
+	}
+
+	results := make([]VectorRecordResponse, len(records))
+	for i, rec := range records {
+		results[i] = VectorRecordResponse{
+			ID:       rec.ID,
+			Text:     rec.Text,
+			Metadata: rec.Metadata,
+			Score:    scores[i],
+		}
+	}
+
+	h.writeSuccess(w, map[string]interface{}{
+		"results": results,
+	})
+}
+
+// DeleteVector handles DELETE /api/embeddings/collections/{name}/{id}.
+func (h *Handler) DeleteVector(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	collection, id := vars["name"], vars["id"]
+
+	store := h.vectorStoreFor(w, r)
+	if store == nil {
+		return
+	}
+
+	if err := store.Delete(collection, id); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.writeSuccess(w, map[string]interface{}{
+		"message": "Record deleted successfully",
+		"id":      id,
+	})
+}
+
+// GetCollectionStats handles GET /api/embeddings/collections/{name}/stats.
+func (h *Handler) GetCollectionStats(w http.ResponseWriter, r *http.Request) {
+	collection := mux.Vars(r)["name"]
+
+	store := h.vectorStoreFor(w, r)
+	if store == nil {
+		return
+	}
+
+	stats, err := store.Stats(collection)
+	if err != nil {
+		h.writeError(w, r, http.StatusInternalServerError, "Failed to read collection stats: "+err.Error())
+		return
+	}
+
+	h.writeSuccess(w, stats)
+}
+
+// RegisterVectorStoreRoutes registers the persistent vector store routes.
+func (h *Handler) RegisterVectorStoreRoutes(router *mux.Router) {
+	collectionsRouter := router.PathPrefix("/api/embeddings/collections/{name}").Subrouter()
+	collectionsRouter.HandleFunc("/upsert", h.UpsertVector).Methods("POST")
+	collectionsRouter.HandleFunc("/query", h.QueryVectorCollection).Methods("POST")
+	collectionsRouter.HandleFunc("/stats", h.GetCollectionStats).Methods("GET")
+	collectionsRouter.HandleFunc("/{id}", h.DeleteVector).Methods("DELETE")
+}
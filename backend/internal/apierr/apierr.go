@@ -0,0 +1,132 @@
+// Package apierr provides a typed error used across internal/api and
+// internal/server so handlers and middleware can surface a stable error
+// code plus a captured stack trace, instead of ad-hoc strings, while still
+// composing with errors.Is/errors.As via Unwrap.
+package apierr
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+)
+
+// Code is a stable, machine-readable error identifier returned to clients
+// in the JSON error envelope's "code" field.
+type Code string
+
+const (
+	// ErrModelMissing indicates the requested model/voice has not been
+	// downloaded or otherwise isn't available to serve.
+	ErrModelMissing Code = "model_missing"
+	// ErrExtractionFailed indicates an embedded or downloaded asset archive
+	// could not be extracted onto disk.
+	ErrExtractionFailed Code = "extraction_failed"
+	// ErrArchiveCorrupt indicates an asset archive's contents could not be
+	// read as a valid zip/tar.gz, or an entry inside it was unsafe to
+	// extract (e.g. a path escaping the target directory).
+	ErrArchiveCorrupt Code = "archive_corrupt"
+	// ErrUnsupportedPlatform indicates no asset archive exists for the
+	// running OS/architecture.
+	ErrUnsupportedPlatform Code = "unsupported_platform"
+	// ErrAssetVerification indicates an extracted asset's checksum didn't
+	// match the manifest.
+	ErrAssetVerification Code = "asset_verification_failed"
+	// ErrBadRequest indicates the caller's request was malformed or
+	// otherwise invalid; used as the generic code for existing
+	// StatusBadRequest call sites.
+	ErrBadRequest Code = "bad_request"
+	// ErrInternal is the generic fallback for unexpected failures,
+	// including recovered panics.
+	ErrInternal Code = "internal_error"
+	// ErrTimeout indicates a request was aborted after exceeding its
+	// deadline (see api.TimeoutMiddleware), either the caller's own
+	// X-Request-Timeout or a per-service default.
+	ErrTimeout Code = "request_timeout"
+)
+
+// Error is a structured API error: a stable Code plus a stack captured at
+// the point it was raised, so a panic or deep internal failure can be
+// logged with enough context to debug without exposing it to the client.
+type Error struct {
+	Code       Code
+	HTTPStatus int
+	Message    string
+	Cause      error
+	Stack      []uintptr
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Cause)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// StackString formats the captured stack as a multi-line string suitable
+// for logging, one "func\n\tfile:line" pair per frame.
+func (e *Error) StackString() string {
+	if len(e.Stack) == 0 {
+		return ""
+	}
+	frames := runtime.CallersFrames(e.Stack)
+	var b strings.Builder
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// New creates an Error with the given code, HTTP status and message,
+// capturing the current stack.
+func New(code Code, httpStatus int, message string) *Error {
+	return &Error{
+		Code:       code,
+		HTTPStatus: httpStatus,
+		Message:    message,
+		Stack:      captureStack(),
+	}
+}
+
+// Wrap creates an Error like New, but records cause as the wrapped error
+// so callers can still errors.Is/errors.As through to it.
+func Wrap(code Code, httpStatus int, message string, cause error) *Error {
+	return &Error{
+		Code:       code,
+		HTTPStatus: httpStatus,
+		Message:    message,
+		Cause:      cause,
+		Stack:      captureStack(),
+	}
+}
+
+// captureStack skips New/Wrap and captureStack itself so the recorded
+// stack starts at the caller that raised the error.
+func captureStack() []uintptr {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	return pcs[:n]
+}
+
+// CodeForStatus returns a generic Code for an HTTP status, used when a
+// call site only has a status code and a plain string message.
+func CodeForStatus(httpStatus int) Code {
+	if httpStatus == http.StatusGatewayTimeout {
+		return ErrTimeout
+	}
+	if httpStatus >= 500 {
+		return ErrInternal
+	}
+	if httpStatus == http.StatusNotFound {
+		return ErrModelMissing
+	}
+	return ErrBadRequest
+}
@@ -0,0 +1,58 @@
+// Package codec encodes raw PCM audio into the containers TTS output can be
+// requested in (WAV, Opus, MP3, FLAC). Each codec beyond WAV lives in its
+// own file and is gated by a disable_codec_* build tag so a downstream
+// build can drop codecs it doesn't need. Opus and MP3 additionally wrap
+// cgo bindings against system libopus/libmp3lame, so they require an
+// explicit codec_opus/codec_mp3 opt-in build tag on top of that: most
+// build environments don't have those libraries installed, and a codec
+// that silently fails to compile everywhere isn't "pluggable", it's
+// broken. FLAC needs no such opt-in since mewkiz/flac is pure Go.
+package codec
+
+import "fmt"
+
+// Format identifies an audio container an Encoder can produce.
+type Format string
+
+const (
+	FormatWAV  Format = "wav"
+	FormatOpus Format = "opus"
+	FormatMP3  Format = "mp3"
+	FormatFLAC Format = "flac"
+)
+
+// Encoder converts raw 16-bit PCM samples into an encoded audio container.
+type Encoder interface {
+	Encode(samples []int16, sampleRate, channels int) ([]byte, error)
+}
+
+// optionalFormats is populated by each optional codec's init() (the ones
+// actually compiled into this build), so Available() doesn't need to know
+// the full set of codecs that could theoretically exist.
+var optionalFormats []Format
+
+// Get returns the Encoder for format, or an error if that codec wasn't
+// compiled into this build.
+func Get(format Format) (Encoder, error) {
+	switch format {
+	case FormatWAV:
+		return wavEncoder{}, nil
+	case FormatOpus:
+		return newOpusEncoder()
+	case FormatMP3:
+		return newMP3Encoder()
+	case FormatFLAC:
+		return newFLACEncoder()
+	default:
+		return nil, fmt.Errorf("unknown audio format %q", format)
+	}
+}
+
+// Available returns every format compiled into this build. WAV is always
+// present since it needs no external codec.
+func Available() []Format {
+	formats := make([]Format, 0, 1+len(optionalFormats))
+	formats = append(formats, FormatWAV)
+	formats = append(formats, optionalFormats...)
+	return formats
+}
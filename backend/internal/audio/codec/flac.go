@@ -0,0 +1,92 @@
+//go:build !disable_codec_flac
+
+package codec
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/mewkiz/flac"
+	"github.com/mewkiz/flac/frame"
+	"github.com/mewkiz/flac/meta"
+)
+
+func init() { optionalFormats = append(optionalFormats, FormatFLAC) }
+
+// flacBlockSize is the number of inter-channel samples encoded per FLAC
+// frame. 4096 is FLAC's conventional default block size.
+const flacBlockSize = 4096
+
+func newFLACEncoder() (Encoder, error) { return flacEncoder{}, nil }
+
+// flacEncoder losslessly packs PCM into FLAC using verbatim (uncompressed)
+// subframes. It skips mewkiz/flac's linear-prediction analysis entirely:
+// Piper's output is short-lived TTS audio, not something worth spending CPU
+// time compressing harder for.
+type flacEncoder struct{}
+
+func (flacEncoder) Encode(samples []int16, sampleRate, channels int) ([]byte, error) {
+	if channels < 1 || channels > 2 {
+		return nil, fmt.Errorf("flac encoder supports 1 or 2 channels, got %d", channels)
+	}
+	chanLayout := frame.ChannelsMono
+	if channels == 2 {
+		chanLayout = frame.ChannelsLR
+	}
+
+	nSamplesPerChannel := len(samples) / channels
+	info := &meta.StreamInfo{
+		BlockSizeMin:  flacBlockSize,
+		BlockSizeMax:  flacBlockSize,
+		SampleRate:    uint32(sampleRate),
+		NChannels:     uint8(channels),
+		BitsPerSample: 16,
+		NSamples:      uint64(nSamplesPerChannel),
+	}
+
+	var buf bytes.Buffer
+	enc, err := flac.NewEncoder(&buf, info)
+	if err != nil {
+		return nil, fmt.Errorf("flac encoder: %w", err)
+	}
+
+	for start := 0; start < nSamplesPerChannel; start += flacBlockSize {
+		end := start + flacBlockSize
+		if end > nSamplesPerChannel {
+			end = nSamplesPerChannel
+		}
+		blockSize := end - start
+
+		subframes := make([]*frame.Subframe, channels)
+		for ch := 0; ch < channels; ch++ {
+			chanSamples := make([]int32, blockSize)
+			for i := 0; i < blockSize; i++ {
+				chanSamples[i] = int32(samples[(start+i)*channels+ch])
+			}
+			subframes[ch] = &frame.Subframe{
+				SubHeader: frame.SubHeader{Pred: frame.PredVerbatim},
+				Samples:   chanSamples,
+				NSamples:  blockSize,
+			}
+		}
+
+		f := &frame.Frame{
+			Header: frame.Header{
+				HasFixedBlockSize: true,
+				BlockSize:         uint16(blockSize),
+				SampleRate:        uint32(sampleRate),
+				Channels:          chanLayout,
+				BitsPerSample:     16,
+			},
+			Subframes: subframes,
+		}
+		if err := enc.WriteFrame(f); err != nil {
+			return nil, fmt.Errorf("flac encoder: write frame: %w", err)
+		}
+	}
+
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("flac encoder: close: %w", err)
+	}
+	return buf.Bytes(), nil
+}
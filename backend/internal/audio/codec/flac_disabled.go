@@ -0,0 +1,9 @@
+//go:build disable_codec_flac
+
+package codec
+
+import "fmt"
+
+func newFLACEncoder() (Encoder, error) {
+	return nil, fmt.Errorf("flac codec support was disabled at build time (disable_codec_flac)")
+}
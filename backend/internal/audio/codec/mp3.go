@@ -0,0 +1,57 @@
+//go:build codec_mp3 && !disable_codec_mp3
+
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	lame "github.com/viert/go-lame"
+)
+
+func init() { optionalFormats = append(optionalFormats, FormatMP3) }
+
+func newMP3Encoder() (Encoder, error) { return mp3Encoder{}, nil }
+
+// mp3Encoder wraps libmp3lame (via cgo).
+type mp3Encoder struct{}
+
+func (mp3Encoder) Encode(samples []int16, sampleRate, channels int) ([]byte, error) {
+	if channels < 1 || channels > 2 {
+		return nil, fmt.Errorf("mp3 encoder supports 1 or 2 channels, got %d", channels)
+	}
+
+	var buf bytes.Buffer
+	enc := lame.NewEncoder(&buf)
+	defer enc.Close()
+
+	if err := enc.SetInSamplerate(sampleRate); err != nil {
+		return nil, fmt.Errorf("mp3 encoder: %w", err)
+	}
+	if err := enc.SetNumChannels(channels); err != nil {
+		return nil, fmt.Errorf("mp3 encoder: %w", err)
+	}
+	if channels > 1 {
+		if err := enc.SetMode(lame.JOINT_STEREO); err != nil {
+			return nil, fmt.Errorf("mp3 encoder: %w", err)
+		}
+	}
+	if err := enc.SetVBR(lame.VBR_DEFAULT); err != nil {
+		return nil, fmt.Errorf("mp3 encoder: %w", err)
+	}
+
+	pcm := make([]byte, len(samples)*2)
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(pcm[i*2:i*2+2], uint16(s))
+	}
+
+	if _, err := enc.Write(pcm); err != nil {
+		return nil, fmt.Errorf("mp3 encoder: encode: %w", err)
+	}
+	if _, err := enc.Flush(); err != nil {
+		return nil, fmt.Errorf("mp3 encoder: flush: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,13 @@
+//go:build !codec_mp3 || disable_codec_mp3
+
+package codec
+
+import "fmt"
+
+// newMP3Encoder is the stub used whenever the cgo-based mp3 codec wasn't
+// opted into at build time. See opus_disabled.go for why this is opt-in
+// rather than opt-out: it wraps libmp3lame via cgo, which isn't present on
+// a default build host.
+func newMP3Encoder() (Encoder, error) {
+	return nil, fmt.Errorf("mp3 codec support was not compiled into this build (build with -tags codec_mp3 and libmp3lame installed)")
+}
@@ -0,0 +1,102 @@
+//go:build codec_opus && !disable_codec_opus
+
+package codec
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// This file implements just enough of RFC 3533 (the Ogg bitstream format) to
+// mux Opus packets for opusEncoder. It always puts one packet per page,
+// which is simpler and slightly less space-efficient than libogg's
+// packet-coalescing but is fully spec-compliant.
+
+const (
+	oggFlagContinued = 1 << 0
+	oggFlagBOS       = 1 << 1
+	oggFlagEOS       = 1 << 2
+)
+
+type oggMuxer struct {
+	w        io.Writer
+	serial   uint32
+	pageSeq  uint32
+	writeErr error
+}
+
+func newOggMuxer(w io.Writer, serial uint32) *oggMuxer {
+	return &oggMuxer{w: w, serial: serial}
+}
+
+func (m *oggMuxer) writePacket(packet []byte, granulePos int64, flags byte) error {
+	if m.writeErr != nil {
+		return m.writeErr
+	}
+
+	segments := lacingValues(len(packet))
+	header := make([]byte, 27+len(segments))
+	copy(header[0:4], "OggS")
+	header[4] = 0 // version
+	header[5] = flags
+	binary.LittleEndian.PutUint64(header[6:14], uint64(granulePos))
+	binary.LittleEndian.PutUint32(header[14:18], m.serial)
+	binary.LittleEndian.PutUint32(header[18:22], m.pageSeq)
+	// header[22:26] (CRC) filled in below, after the full page is known.
+	header[26] = byte(len(segments))
+	copy(header[27:], segments)
+
+	page := make([]byte, 0, len(header)+len(packet))
+	page = append(page, header...)
+	page = append(page, packet...)
+
+	crc := oggCRC32(page)
+	binary.LittleEndian.PutUint32(page[22:26], crc)
+
+	m.pageSeq++
+	if _, err := m.w.Write(page); err != nil {
+		m.writeErr = err
+		return err
+	}
+	return nil
+}
+
+// lacingValues builds an Ogg segment table (RFC 3533 section 6) for a
+// packet of the given length: one 255 entry per full 255-byte run, then a
+// final entry (possibly 0) with the remainder.
+func lacingValues(packetLen int) []byte {
+	segments := make([]byte, 0, packetLen/255+1)
+	for packetLen >= 255 {
+		segments = append(segments, 255)
+		packetLen -= 255
+	}
+	segments = append(segments, byte(packetLen))
+	return segments
+}
+
+var oggCRCTable [256]uint32
+
+func init() {
+	const poly = 0x04c11db7
+	for i := 0; i < 256; i++ {
+		crc := uint32(i) << 24
+		for j := 0; j < 8; j++ {
+			if crc&0x80000000 != 0 {
+				crc = (crc << 1) ^ poly
+			} else {
+				crc <<= 1
+			}
+		}
+		oggCRCTable[i] = crc
+	}
+}
+
+// oggCRC32 computes Ogg's page checksum (RFC 3533 section 6), which must be
+// calculated with the page's own CRC field zeroed.
+func oggCRC32(data []byte) uint32 {
+	var crc uint32
+	for _, b := range data {
+		crc = (crc << 8) ^ oggCRCTable[byte(crc>>24)^b]
+	}
+	return crc
+}
@@ -0,0 +1,139 @@
+//go:build codec_opus && !disable_codec_opus
+
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/hraban/opus"
+)
+
+func init() { optionalFormats = append(optionalFormats, FormatOpus) }
+
+func newOpusEncoder() (Encoder, error) { return opusEncoder{}, nil }
+
+// opusFrameSamples is the number of samples per channel encoded into each
+// Opus frame: 20ms at Opus's mandatory internal 48kHz clock, the frame
+// duration most encoders default to.
+const opusFrameSamples = 960
+const opusSampleRate = 48000
+
+// opusEncoder wraps libopus (via cgo) and packages the result as a minimal
+// single-stream Ogg/Opus file (RFC 7845), since that's the container every
+// Opus-aware player and the Electron frontend's <audio> element expect.
+// Input is resampled to Opus's fixed 48kHz clock first; libopus doesn't
+// support Piper's native 22.05kHz.
+type opusEncoder struct{}
+
+func (opusEncoder) Encode(samples []int16, sampleRate, channels int) ([]byte, error) {
+	if channels < 1 || channels > 2 {
+		return nil, fmt.Errorf("opus encoder supports 1 or 2 channels, got %d", channels)
+	}
+
+	resampled := resampleLinearInt16(samples, sampleRate, opusSampleRate, channels)
+
+	application := opus.AppVoIP
+	enc, err := opus.NewEncoder(opusSampleRate, channels, application)
+	if err != nil {
+		return nil, fmt.Errorf("opus encoder: %w", err)
+	}
+
+	var buf bytes.Buffer
+	mux := newOggMuxer(&buf, 1)
+
+	head := encodeOpusHead(channels, sampleRate)
+	if err := mux.writePacket(head, 0, oggFlagBOS); err != nil {
+		return nil, fmt.Errorf("opus encoder: write OpusHead: %w", err)
+	}
+	if err := mux.writePacket(encodeOpusTags(), 0, 0); err != nil {
+		return nil, fmt.Errorf("opus encoder: write OpusTags: %w", err)
+	}
+
+	frameLen := opusFrameSamples * channels
+	out := make([]byte, 4000) // worst-case Opus packet size per the libopus docs
+	var granule int64
+
+	for start := 0; start < len(resampled); start += frameLen {
+		end := start + frameLen
+		frame := make([]int16, frameLen)
+		if end > len(resampled) {
+			copy(frame, resampled[start:])
+		} else {
+			copy(frame, resampled[start:end])
+		}
+
+		n, err := enc.Encode(frame, out)
+		if err != nil {
+			return nil, fmt.Errorf("opus encoder: encode frame: %w", err)
+		}
+
+		granule += opusFrameSamples
+		flags := byte(0)
+		if end >= len(resampled) {
+			flags = oggFlagEOS
+		}
+		if err := mux.writePacket(append([]byte(nil), out[:n]...), granule, flags); err != nil {
+			return nil, fmt.Errorf("opus encoder: write frame: %w", err)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// encodeOpusHead builds the mandatory first packet of an Ogg/Opus stream
+// (RFC 7845 section 5.1). inputSampleRate is informational only; Opus
+// always decodes at 48kHz regardless of what's stored here.
+func encodeOpusHead(channels, inputSampleRate int) []byte {
+	buf := make([]byte, 19)
+	copy(buf[0:8], "OpusHead")
+	buf[8] = 1 // version
+	buf[9] = byte(channels)
+	binary.LittleEndian.PutUint16(buf[10:12], 0) // pre-skip
+	binary.LittleEndian.PutUint32(buf[12:16], uint32(inputSampleRate))
+	binary.LittleEndian.PutUint16(buf[16:18], 0) // output gain
+	buf[18] = 0                                  // channel mapping family (single stream / mono or stereo)
+	return buf
+}
+
+// encodeOpusTags builds the mandatory second packet of an Ogg/Opus stream
+// (RFC 7845 section 5.2), with no user comments.
+func encodeOpusTags() []byte {
+	vendor := "alice-backend"
+	buf := make([]byte, 8+4+len(vendor)+4)
+	copy(buf[0:8], "OpusTags")
+	binary.LittleEndian.PutUint32(buf[8:12], uint32(len(vendor)))
+	copy(buf[12:12+len(vendor)], vendor)
+	binary.LittleEndian.PutUint32(buf[12+len(vendor):16+len(vendor)], 0)
+	return buf
+}
+
+// resampleLinearInt16 converts interleaved PCM from srcRate to dstRate
+// using linear interpolation, mirroring whisper.resampleLinear's approach
+// for the float32 case.
+func resampleLinearInt16(samples []int16, srcRate, dstRate, channels int) []int16 {
+	if srcRate <= 0 || dstRate <= 0 || srcRate == dstRate || len(samples) == 0 {
+		return samples
+	}
+
+	framesIn := len(samples) / channels
+	ratio := float64(srcRate) / float64(dstRate)
+	framesOut := int(float64(framesIn) / ratio)
+	out := make([]int16, framesOut*channels)
+
+	for i := 0; i < framesOut; i++ {
+		srcPos := float64(i) * ratio
+		idx := int(srcPos)
+		frac := srcPos - float64(idx)
+		for ch := 0; ch < channels; ch++ {
+			a := float64(samples[idx*channels+ch])
+			b := a
+			if idx+1 < framesIn {
+				b = float64(samples[(idx+1)*channels+ch])
+			}
+			out[i*channels+ch] = int16(a*(1-frac) + b*frac)
+		}
+	}
+	return out
+}
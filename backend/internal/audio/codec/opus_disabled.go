@@ -0,0 +1,14 @@
+//go:build !codec_opus || disable_codec_opus
+
+package codec
+
+import "fmt"
+
+// newOpusEncoder is the stub used whenever the cgo-based opus codec wasn't
+// opted into at build time. Opus support wraps libopus via cgo (see
+// opus.go), so it requires both the codec_opus build tag and libopus's
+// development headers/library to be present on the build host; neither is
+// true of a default build, so it's opt-in rather than opt-out like flac.
+func newOpusEncoder() (Encoder, error) {
+	return nil, fmt.Errorf("opus codec support was not compiled into this build (build with -tags codec_opus and libopus installed)")
+}
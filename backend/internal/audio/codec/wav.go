@@ -0,0 +1,36 @@
+package codec
+
+import "encoding/binary"
+
+// wavEncoder wraps PCM samples in a canonical RIFF/WAVE header. It never
+// needs disabling since it has no external dependency, so it has no
+// build tag and isn't added to optionalFormats.
+type wavEncoder struct{}
+
+func (wavEncoder) Encode(samples []int16, sampleRate, channels int) ([]byte, error) {
+	dataSize := len(samples) * 2
+	buf := make([]byte, 44+dataSize)
+
+	copy(buf[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(36+dataSize))
+	copy(buf[8:12], "WAVE")
+
+	copy(buf[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(buf[16:20], 16)
+	binary.LittleEndian.PutUint16(buf[20:22], 1) // PCM
+	binary.LittleEndian.PutUint16(buf[22:24], uint16(channels))
+	binary.LittleEndian.PutUint32(buf[24:28], uint32(sampleRate))
+	byteRate := sampleRate * channels * 2
+	binary.LittleEndian.PutUint32(buf[28:32], uint32(byteRate))
+	binary.LittleEndian.PutUint16(buf[32:34], uint16(channels*2))
+	binary.LittleEndian.PutUint16(buf[34:36], 16) // bits per sample
+
+	copy(buf[36:40], "data")
+	binary.LittleEndian.PutUint32(buf[40:44], uint32(dataSize))
+
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(buf[44+i*2:46+i*2], uint16(s))
+	}
+
+	return buf, nil
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds the application configuration
@@ -22,6 +24,27 @@ type ModelsConfig struct {
 	Whisper WhisperConfig
 	Piper   PiperConfig
 	MiniLM  MiniLMConfig
+
+	// STTVariants and EmbeddingsVariants name additional model paths that
+	// models.Manager lazily initializes on first use (see
+	// Manager.GetSTTServiceFor/GetEmbeddingServiceFor) instead of loading
+	// eagerly at startup like Whisper/MiniLM above. Keys are the names
+	// clients select via the X-Alice-Model request header or a
+	// /v1/{model}/... URL prefix.
+	STTVariants        map[string]string
+	EmbeddingsVariants map[string]string
+	// MaxLoadedVariants bounds how many lazily-initialized variants per
+	// family Manager keeps warm at once; past this it evicts the
+	// least-recently-used variant to bound memory/VRAM use. 0 means
+	// unlimited.
+	MaxLoadedVariants int
+
+	// AssetMirrorURL, if set, is the base URL of an HTTP mirror (e.g. a
+	// GitHub Releases download URL) that embedded.AssetManager falls back
+	// to for whisper/piper archives that aren't compiled into the binary
+	// and aren't already cached locally. Empty disables the HTTP fallback,
+	// leaving only the embedded assets and local cache.
+	AssetMirrorURL string
 }
 
 // WhisperConfig holds Whisper model configuration
@@ -32,11 +55,28 @@ type WhisperConfig struct {
 // PiperConfig holds Piper model configuration
 type PiperConfig struct {
 	Path string
+
+	// Provider selects the TTS backend: "" or "piper" (default) uses the
+	// bundled Piper voices; "coqui", "openai", or "google" route through
+	// that cloud/self-hosted provider instead (see piper.Backend).
+	Provider          string
+	CoquiBaseURL      string
+	CoquiCommand      string
+	OpenAIAPIKey      string
+	OpenAIBaseURL     string
+	GoogleCloudAPIKey string
 }
 
 // MiniLMConfig holds MiniLM model configuration
 type MiniLMConfig struct {
-	Path string
+	Path              string
+	ExecutionProvider string
+
+	// WorkerPath, if set, runs the embedding model as a supervised
+	// cmd/minilm-worker subprocess (see models.Manager and pkg/grpc/base)
+	// instead of loading ONNX Runtime directly into the API server. Empty
+	// (the default) keeps the existing in-process behavior.
+	WorkerPath string
 }
 
 // FeaturesConfig holds feature flags
@@ -44,6 +84,35 @@ type FeaturesConfig struct {
 	STT        bool
 	TTS        bool
 	Embeddings bool
+
+	// EnableMetrics turns on GET /metrics (Prometheus exposition format)
+	// and the per-backend health/latency fields on GET /api/status. The
+	// monitor itself (circuit breaker, latency tracking) always runs
+	// regardless of this flag - it only gates the extra endpoints.
+	EnableMetrics bool
+	// ErrorRateThreshold is the fraction of failed requests (in [0,1])
+	// that trips a backend's circuit breaker.
+	ErrorRateThreshold float64
+	// CircuitBreakerBackoff is the initial delay before models.Manager
+	// retries re-initializing a tripped backend; it doubles on each
+	// failed retry up to CircuitBreakerMaxBackoff.
+	CircuitBreakerBackoff    time.Duration
+	CircuitBreakerMaxBackoff time.Duration
+
+	// RequestTimeout bounds how long api.TimeoutMiddleware lets a request
+	// run before cancelling its context and responding 504, for any request
+	// whose path doesn't match one of the per-service timeouts below (or
+	// when a caller's X-Request-Timeout header doesn't apply). 0 disables
+	// the default, leaving only X-Request-Timeout and the per-service
+	// fields able to impose a deadline.
+	RequestTimeout time.Duration
+	// STTTimeout/TTSTimeout/EmbeddingsTimeout override RequestTimeout for
+	// requests under /api/stt, /api/tts, and /api/embeddings (plus
+	// /api/rerank) respectively, since a long transcription or synthesis
+	// job legitimately needs more room than a single embedding call.
+	STTTimeout        time.Duration
+	TTSTimeout        time.Duration
+	EmbeddingsTimeout time.Duration
 }
 
 // LoadConfig loads configuration from environment variables
@@ -57,16 +126,36 @@ func LoadConfig() *Config {
 				Path: getEnv("WHISPER_MODEL_PATH", "./models/whisper-base"),
 			},
 			Piper: PiperConfig{
-				Path: getEnv("PIPER_MODEL_PATH", "./models/piper"),
+				Path:              getEnv("PIPER_MODEL_PATH", "./models/piper"),
+				Provider:          getEnv("TTS_PROVIDER", "piper"),
+				CoquiBaseURL:      getEnv("COQUI_TTS_BASE_URL", ""),
+				CoquiCommand:      getEnv("COQUI_TTS_COMMAND", ""),
+				OpenAIAPIKey:      getEnv("OPENAI_TTS_API_KEY", ""),
+				OpenAIBaseURL:     getEnv("OPENAI_TTS_BASE_URL", ""),
+				GoogleCloudAPIKey: getEnv("GOOGLE_CLOUD_TTS_API_KEY", ""),
 			},
 			MiniLM: MiniLMConfig{
-				Path: getEnv("MINILM_MODEL_PATH", "./models/minilm"),
+				Path:              getEnv("MINILM_MODEL_PATH", "./models/minilm"),
+				ExecutionProvider: getEnv("MINILM_EXECUTION_PROVIDER", "auto"),
+				WorkerPath:        getEnv("MINILM_WORKER_PATH", ""),
 			},
+			STTVariants:        getMapEnv("STT_MODEL_VARIANTS"),
+			EmbeddingsVariants: getMapEnv("EMBEDDINGS_MODEL_VARIANTS"),
+			MaxLoadedVariants:  getIntEnv("MAX_LOADED_MODEL_VARIANTS", 3),
+			AssetMirrorURL:     getEnv("ASSET_MIRROR_URL", ""),
 		},
 		Features: FeaturesConfig{
-			STT:        getBoolEnv("ENABLE_STT", true),
-			TTS:        getBoolEnv("ENABLE_TTS", true),
-			Embeddings: getBoolEnv("ENABLE_EMBEDDINGS", true),
+			STT:                      getBoolEnv("ENABLE_STT", true),
+			TTS:                      getBoolEnv("ENABLE_TTS", true),
+			Embeddings:               getBoolEnv("ENABLE_EMBEDDINGS", true),
+			EnableMetrics:            getBoolEnv("ENABLE_METRICS", false),
+			ErrorRateThreshold:       getFloatEnv("CIRCUIT_BREAKER_ERROR_RATE_THRESHOLD", 0.5),
+			CircuitBreakerBackoff:    getDurationEnv("CIRCUIT_BREAKER_BACKOFF", time.Second),
+			CircuitBreakerMaxBackoff: getDurationEnv("CIRCUIT_BREAKER_MAX_BACKOFF", 5*time.Minute),
+			RequestTimeout:           getDurationEnv("REQUEST_TIMEOUT", 30*time.Second),
+			STTTimeout:               getDurationEnv("STT_REQUEST_TIMEOUT", 60*time.Second),
+			TTSTimeout:               getDurationEnv("TTS_REQUEST_TIMEOUT", 30*time.Second),
+			EmbeddingsTimeout:        getDurationEnv("EMBEDDINGS_REQUEST_TIMEOUT", 15*time.Second),
 		},
 	}
 }
@@ -88,3 +177,53 @@ func getBoolEnv(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+// getFloatEnv gets a float64 environment variable with a default value
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+// getDurationEnv gets a time.Duration environment variable with a default value
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if durationValue, err := time.ParseDuration(value); err == nil {
+			return durationValue
+		}
+	}
+	return defaultValue
+}
+
+// getIntEnv gets an integer environment variable with a default value
+func getIntEnv(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
+// getMapEnv parses a comma-separated "name=value,name2=value2" environment
+// variable into a map, e.g. STT_MODEL_VARIANTS=whisper-tiny=models/whisper-tiny.bin.
+// Malformed entries (missing "=") are skipped. Returns an empty, non-nil map
+// if the variable is unset.
+func getMapEnv(key string) map[string]string {
+	result := make(map[string]string)
+	value := os.Getenv(key)
+	if value == "" {
+		return result
+	}
+	for _, pair := range strings.Split(value, ",") {
+		name, path, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			continue
+		}
+		result[name] = path
+	}
+	return result
+}
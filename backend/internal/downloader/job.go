@@ -0,0 +1,309 @@
+package downloader
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// maxChecksumRetries bounds how many times a job re-downloads from scratch
+// after a checksum mismatch before giving up. A mismatch usually means a
+// transient corruption (a flaky network, a server that served a partial
+// range incorrectly), so it's worth a few clean retries rather than failing
+// the job outright the first time.
+const maxChecksumRetries = 3
+
+// checksumMismatchError distinguishes a failed digest check from every other
+// download failure, so Manager.run knows a retry is worth attempting.
+type checksumMismatchError struct {
+	algo      string
+	got, want string
+}
+
+func (e *checksumMismatchError) Error() string {
+	return fmt.Sprintf("%s checksum mismatch: got %s, want %s", e.algo, e.got, e.want)
+}
+
+// JobStatus is the lifecycle state of a tracked download.
+type JobStatus string
+
+const (
+	JobPending     JobStatus = "pending"
+	JobDownloading JobStatus = "downloading"
+	JobVerifying   JobStatus = "verifying"
+	JobDone        JobStatus = "done"
+	JobFailed      JobStatus = "failed"
+)
+
+// Progress is a point-in-time snapshot of a download job's progress.
+type Progress struct {
+	Bytes   int64         `json:"bytes"`
+	Total   int64         `json:"total"`
+	Percent float64       `json:"percent"`
+	ETA     time.Duration `json:"eta"`
+}
+
+// Job tracks a single resumable, checksum-verified download.
+type Job struct {
+	ID     string `json:"id"`
+	URL    string `json:"url"`
+	Dest   string `json:"dest"`
+	SHA256 string `json:"-"` // expected hex digest; empty skips verification
+	MD5    string `json:"-"` // expected hex digest, checked when SHA256 is empty; some manifests (e.g. Piper's voices.json) only publish MD5
+
+	mu        sync.RWMutex
+	status    JobStatus
+	progress  Progress
+	err       error
+	startedAt time.Time
+}
+
+// Status returns the job's current lifecycle state.
+func (j *Job) Status() JobStatus {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.status
+}
+
+// Progress returns a snapshot of the job's current progress.
+func (j *Job) Progress() Progress {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.progress
+}
+
+// Err returns the error that failed the job, if any.
+func (j *Job) Err() error {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.err
+}
+
+func (j *Job) setStatus(s JobStatus) {
+	j.mu.Lock()
+	j.status = s
+	j.mu.Unlock()
+}
+
+func (j *Job) fail(err error) {
+	j.mu.Lock()
+	j.status = JobFailed
+	j.err = err
+	j.mu.Unlock()
+}
+
+func (j *Job) setTotal(total int64) {
+	j.mu.Lock()
+	j.progress.Total = total
+	j.mu.Unlock()
+}
+
+func (j *Job) updateProgress(written, total int64, since time.Time) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.progress.Bytes = written
+	j.progress.Total = total
+	if total > 0 {
+		j.progress.Percent = float64(written) * 100.0 / float64(total)
+	}
+	if elapsed := time.Since(since); elapsed > 0 && written > 0 && total > written {
+		rate := float64(written) / elapsed.Seconds()
+		if rate > 0 {
+			j.progress.ETA = time.Duration(float64(total-written)/rate) * time.Second
+		}
+	}
+}
+
+// Manager tracks in-flight and completed download jobs, keyed by job ID, so
+// long-running model downloads can be started once and polled from a
+// separate request (e.g. GetModelDownloadStatus).
+type Manager struct {
+	mu     sync.RWMutex
+	jobs   map[string]*Job
+	client *http.Client
+}
+
+// NewManager creates a download job manager.
+func NewManager() *Manager {
+	return &Manager{
+		jobs:   make(map[string]*Job),
+		client: &http.Client{},
+	}
+}
+
+// Start launches a resumable, checksum-verified download in the background
+// and returns immediately with its Job. If a download for id is already in
+// flight, that job is returned instead of starting a duplicate.
+func (m *Manager) Start(id, url, dest, sha256Hex string) *Job {
+	return m.start(id, url, dest, sha256Hex, "")
+}
+
+// StartMD5 is the MD5 analogue of Start, for sources (e.g. Piper's
+// voices.json manifest) that only publish an MD5 digest per file.
+func (m *Manager) StartMD5(id, url, dest, md5Hex string) *Job {
+	return m.start(id, url, dest, "", md5Hex)
+}
+
+func (m *Manager) start(id, url, dest, sha256Hex, md5Hex string) *Job {
+	m.mu.Lock()
+	if existing, ok := m.jobs[id]; ok {
+		switch existing.Status() {
+		case JobPending, JobDownloading, JobVerifying:
+			m.mu.Unlock()
+			return existing
+		}
+	}
+
+	job := &Job{ID: id, URL: url, Dest: dest, SHA256: sha256Hex, MD5: md5Hex, status: JobPending, startedAt: time.Now()}
+	m.jobs[id] = job
+	m.mu.Unlock()
+
+	go m.run(job)
+	return job
+}
+
+// Status returns the job tracked under id, if any.
+func (m *Manager) Status(id string) (*Job, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	job, ok := m.jobs[id]
+	return job, ok
+}
+
+func (m *Manager) run(job *Job) {
+	var err error
+	for attempt := 0; attempt <= maxChecksumRetries; attempt++ {
+		job.setStatus(JobDownloading)
+		err = m.download(job)
+		if err == nil {
+			job.setStatus(JobDone)
+			return
+		}
+
+		var mismatch *checksumMismatchError
+		if !errors.As(err, &mismatch) {
+			break
+		}
+	}
+	job.fail(err)
+}
+
+// download streams job.URL to job.Dest+".part", resuming via HTTP Range if a
+// partial file already exists, verifies the expected digest once complete,
+// then renames the part file into place.
+func (m *Manager) download(job *Job) error {
+	if err := os.MkdirAll(filepath.Dir(job.Dest), 0755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+
+	partPath := job.Dest + ".part"
+	hasher := newJobHasher(job)
+
+	var resumeFrom int64
+	if fi, err := os.Stat(partPath); err == nil {
+		resumeFrom = fi.Size()
+		if existing, err := os.Open(partPath); err == nil {
+			io.Copy(hasher, existing)
+			existing.Close()
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, job.URL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	if resumeFrom > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", resumeFrom))
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var out *os.File
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		out, err = os.OpenFile(partPath, os.O_APPEND|os.O_WRONLY, 0644)
+	case http.StatusOK:
+		// Server ignored (or we didn't send) the Range request; restart clean.
+		resumeFrom = 0
+		hasher.Reset()
+		out, err = os.Create(partPath)
+	default:
+		return fmt.Errorf("download failed with status: %d", resp.StatusCode)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open part file: %w", err)
+	}
+	defer out.Close()
+
+	total := resp.ContentLength
+	if total >= 0 && resp.StatusCode == http.StatusPartialContent {
+		total += resumeFrom
+	}
+	job.setTotal(total)
+
+	tee := io.TeeReader(resp.Body, hasher)
+	buf := make([]byte, 256*1024)
+	written := resumeFrom
+	start := time.Now()
+
+	for {
+		n, rerr := tee.Read(buf)
+		if n > 0 {
+			if _, werr := out.Write(buf[:n]); werr != nil {
+				return fmt.Errorf("failed to write part file: %w", werr)
+			}
+			written += int64(n)
+			job.updateProgress(written, total, start)
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return fmt.Errorf("failed to read response body: %w", rerr)
+		}
+	}
+
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to close part file: %w", err)
+	}
+
+	if want := job.SHA256; want != "" {
+		job.setStatus(JobVerifying)
+		if sum := hex.EncodeToString(hasher.Sum(nil)); sum != want {
+			os.Remove(partPath)
+			return &checksumMismatchError{algo: "sha256", got: sum, want: want}
+		}
+	} else if want := job.MD5; want != "" {
+		job.setStatus(JobVerifying)
+		if sum := hex.EncodeToString(hasher.Sum(nil)); sum != want {
+			os.Remove(partPath)
+			return &checksumMismatchError{algo: "md5", got: sum, want: want}
+		}
+	}
+
+	return os.Rename(partPath, job.Dest)
+}
+
+// newJobHasher picks the digest algorithm matching whichever expected hash
+// job carries (SHA256 takes priority since it's the stronger guarantee),
+// defaulting to SHA-256 when neither is set; in that case the digest is
+// computed as the part file streams in but never compared or kept.
+func newJobHasher(job *Job) hash.Hash {
+	if job.MD5 != "" && job.SHA256 == "" {
+		return md5.New()
+	}
+	return sha256.New()
+}
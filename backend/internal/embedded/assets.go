@@ -5,15 +5,20 @@ import (
 	"archive/zip"
 	"compress/gzip"
 	"context"
-	"crypto/md5"
+	"crypto/sha256"
 	"embed"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
+	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+
+	"alice-backend/internal/apierr"
 )
 
 // Embed all platform-specific binaries and data files
@@ -25,6 +30,56 @@ var EmbeddedAssets embed.FS
 type AssetManager struct {
 	baseDir string
 	cache   map[string]string // asset -> extracted path
+
+	// checksums records the expected SHA-256 of each extracted file that was
+	// covered by an archive's manifest.json, so VerifyAsset can re-check it
+	// later without needing the manifest reloaded.
+	checksums map[string]string
+
+	// stores is the ordered fallback chain AssetManager resolves asset paths
+	// through: compiled-in assets first, then anything already cached on
+	// disk from a previous download, then (if configured) an HTTP mirror.
+	stores []AssetStore
+
+	// reporter, if set, receives ProgressEvents as EnsureAssets resolves,
+	// extracts, and verifies assets. Nil (the default) disables reporting
+	// entirely - see SetProgressReporter.
+	reporter ProgressReporter
+}
+
+// assetManifest lists the expected SHA-256 checksum of each file in an
+// embedded archive, keyed by the file's path relative to the archive root
+// (forward-slash separated, matching zip/tar entry names). EnsureAssets uses
+// it to detect a corrupted or tampered archive before the binary/model it
+// contains is ever run. A manifest is optional: archives shipped without one
+// are extracted without verification rather than treated as a hard failure,
+// since Ed25519-signing tooling for generating these manifests doesn't exist
+// in this repo yet.
+type assetManifest struct {
+	Files map[string]string `json:"files"`
+}
+
+// loadManifest reads the manifest for an archive resolved through am.stores,
+// stored alongside it under assets/manifests/<archive-base-name>.json (e.g.
+// the manifest for assets/piper/piper_linux_amd64.tar.gz is
+// assets/manifests/piper_linux_amd64.json). Returns (nil, nil) if no
+// manifest is available for this archive from any store.
+func (am *AssetManager) loadManifest(archivePath string) (*assetManifest, error) {
+	base := filepath.Base(archivePath)
+	base = strings.TrimSuffix(base, ".tar.gz")
+	base = strings.TrimSuffix(base, ".zip")
+	manifestPath := fmt.Sprintf("assets/manifests/%s.json", base)
+
+	data, err := am.readAsset(manifestPath)
+	if err != nil {
+		return nil, nil
+	}
+
+	var manifest assetManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", manifestPath, err)
+	}
+	return &manifest, nil
 }
 
 // PlatformInfo holds platform-specific asset information
@@ -104,12 +159,120 @@ func GetProductionBaseDirectory() string {
 	return exeDir
 }
 
-// NewAssetManager creates a new asset manager with a base directory
+// NewAssetManager creates a new asset manager with a base directory,
+// resolving assets through the compiled-in embed.FS first and a local
+// baseDir/cache directory second. Call SetAssetMirror afterwards to add an
+// HTTP mirror as a third fallback for assets that aren't bundled.
 func NewAssetManager(baseDir string) *AssetManager {
+	return NewAssetManagerWithStores(baseDir,
+		newEmbedStore(EmbeddedAssets),
+		newDirStore(filepath.Join(baseDir, "cache")),
+	)
+}
+
+// NewAssetManagerWithStores creates an asset manager backed by an explicit,
+// ordered chain of stores, for callers that want full control (e.g. tests,
+// or omitting the HTTP mirror entirely).
+func NewAssetManagerWithStores(baseDir string, stores ...AssetStore) *AssetManager {
 	return &AssetManager{
-		baseDir: baseDir,
-		cache:   make(map[string]string),
+		baseDir:   baseDir,
+		cache:     make(map[string]string),
+		checksums: make(map[string]string),
+		stores:    stores,
+	}
+}
+
+// SetAssetMirror appends an HTTP mirror to the end of the store chain, so
+// archives missing from both the embedded assets and the local cache are
+// fetched from baseURL (e.g. a GitHub Releases download URL) and cached
+// under baseDir/cache for next time. A call with an empty baseURL is a
+// no-op, since whisper/piper construct their AssetManager before they know
+// whether a mirror is configured.
+func (am *AssetManager) SetAssetMirror(baseURL string) {
+	if baseURL == "" {
+		return
+	}
+	am.stores = append(am.stores, newHTTPStore(baseURL, filepath.Join(am.baseDir, "cache")))
+}
+
+// SetProgressReporter installs reporter as the destination for EnsureAssets'
+// ProgressEvents. A nil reporter is a no-op, since whisper/piper construct
+// their AssetManager before they know whether anything is subscribed to
+// progress events.
+func (am *AssetManager) SetProgressReporter(reporter ProgressReporter) {
+	if reporter == nil {
+		return
+	}
+	am.reporter = reporter
+}
+
+// openAsset opens path from the first store in the chain that has it.
+func (am *AssetManager) openAsset(path string) (fs.File, error) {
+	var lastErr error
+	for _, store := range am.stores {
+		f, err := store.Open(path)
+		if err == nil {
+			return f, nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no asset store configured for %s", path)
+	}
+	return nil, lastErr
+}
+
+// readAsset reads path fully from the first store in the chain that has it.
+func (am *AssetManager) readAsset(path string) ([]byte, error) {
+	f, err := am.openAsset(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// readAssetWithProgress is readAsset, plus reporting cumulative bytes read
+// to am.reporter (if set) under the "download" phase - covering both an
+// actual HTTP mirror fetch and the otherwise-instant read of an embedded or
+// locally cached asset, so a subscriber sees one consistent phase for
+// "the archive is being acquired" regardless of which store served it.
+func (am *AssetManager) readAssetWithProgress(path, service string) ([]byte, error) {
+	f, err := am.openAsset(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if am.reporter == nil {
+		return io.ReadAll(f)
+	}
+
+	var total int64
+	if info, statErr := f.Stat(); statErr == nil {
+		total = info.Size()
+	}
+
+	reader := &progressCountingReader{
+		Reader:   f,
+		reporter: am.reporter,
+		service:  service,
+		phase:    "download",
+		file:     path,
+		total:    total,
 	}
+	return io.ReadAll(reader)
+}
+
+// hasAsset reports whether any store in the chain currently has path,
+// without downloading/reading it.
+func (am *AssetManager) hasAsset(path string) bool {
+	for _, store := range am.stores {
+		if _, err := store.Stat(path); err == nil {
+			return true
+		}
+	}
+	return false
 }
 
 // EnsureAssets extracts all required assets for the current platform
@@ -126,19 +289,36 @@ func (am *AssetManager) EnsureAssets(ctx context.Context) error {
 		return fmt.Errorf("failed to create models directory: %w", err)
 	}
 
+	var firstErr error
+
 	// Extract Whisper assets
 	if err := am.extractWhisperAssets(ctx, info); err != nil {
 		log.Printf("Warning: Failed to extract Whisper assets: %v", err)
+		firstErr = err
 	}
 
 	// Extract Piper assets
 	if err := am.extractPiperAssets(ctx, info); err != nil {
 		log.Printf("Warning: Failed to extract Piper assets: %v", err)
+		if firstErr == nil {
+			firstErr = err
+		}
 	}
 
 	// Extract voice models
 	if err := am.extractVoiceModels(ctx, info); err != nil {
 		log.Printf("Warning: Failed to extract voice models: %v", err)
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if am.reporter != nil {
+		if firstErr != nil {
+			am.reporter.Report(ProgressEvent{Phase: "error", Error: firstErr.Error()})
+		} else {
+			am.reporter.Report(ProgressEvent{Phase: "done"})
+		}
 	}
 
 	return nil
@@ -151,16 +331,17 @@ func (am *AssetManager) extractWhisperAssets(ctx context.Context, info *Platform
 	
 	log.Printf("Checking for Whisper assets: %s", archivePath)
 	
-	// Check if embedded archive exists
-	if _, err := EmbeddedAssets.Open(archivePath); err != nil {
-		log.Printf("No embedded whisper archive found for platform %s/%s - will use download fallback", info.OS, info.Arch)
-		return fmt.Errorf("whisper archive not embedded for platform %s/%s", info.OS, info.Arch)
+	// Check if the archive is resolvable through the store chain (embedded,
+	// local cache, or HTTP mirror)
+	if !am.hasAsset(archivePath) {
+		log.Printf("No whisper archive available for platform %s/%s in any asset store", info.OS, info.Arch)
+		return apierr.New(apierr.ErrUnsupportedPlatform, http.StatusNotImplemented, fmt.Sprintf("whisper archive not available for platform %s/%s", info.OS, info.Arch))
 	}
 	
 	log.Printf("Extracting embedded Whisper assets from: %s", archivePath)
 	// Extract archive to bin directory
 	binDir := filepath.Join(am.baseDir, "bin")
-	return am.extractEmbeddedZip(archivePath, binDir)
+	return am.extractEmbeddedZip(archivePath, binDir, "stt")
 }
 
 // extractPiperAssets extracts Piper binary and espeak-ng data
@@ -183,18 +364,19 @@ func (am *AssetManager) extractPiperAssets(ctx context.Context, info *PlatformIn
 	archivePath := fmt.Sprintf("assets/piper/%s", archiveName)
 	log.Printf("Checking for Piper assets: %s", archivePath)
 	
-	// Check if embedded archive exists
-	if _, err := EmbeddedAssets.Open(archivePath); err != nil {
-		log.Printf("No embedded piper archive found for platform %s/%s - will use download fallback", info.OS, info.Arch)
-		return fmt.Errorf("piper archive not embedded for platform %s/%s", info.OS, info.Arch)
+	// Check if the archive is resolvable through the store chain (embedded,
+	// local cache, or HTTP mirror)
+	if !am.hasAsset(archivePath) {
+		log.Printf("No piper archive available for platform %s/%s in any asset store", info.OS, info.Arch)
+		return apierr.New(apierr.ErrUnsupportedPlatform, http.StatusNotImplemented, fmt.Sprintf("piper archive not available for platform %s/%s", info.OS, info.Arch))
 	}
 	
 	log.Printf("Extracting embedded Piper assets from: %s", archivePath)
 	binDir := filepath.Join(am.baseDir, "bin")
 	if isZip {
-		return am.extractEmbeddedZip(archivePath, binDir)
+		return am.extractEmbeddedZip(archivePath, binDir, "tts")
 	} else {
-		return am.extractEmbeddedTarGz(archivePath, binDir)
+		return am.extractEmbeddedTarGz(archivePath, binDir, "tts")
 	}
 }
 
@@ -204,15 +386,15 @@ func (am *AssetManager) extractVoiceModels(ctx context.Context, info *PlatformIn
 	
 	// Extract Whisper model
 	whisperModelPath := fmt.Sprintf("assets/models/%s", info.WhisperModel)
-	if _, err := EmbeddedAssets.Open(whisperModelPath); err == nil {
+	if am.hasAsset(whisperModelPath) {
 		targetPath := filepath.Join(modelsDir, info.WhisperModel)
-		if err := am.extractEmbeddedFile(whisperModelPath, targetPath); err != nil {
+		if err := am.extractEmbeddedFile(whisperModelPath, targetPath, "voices"); err != nil {
 			log.Printf("Warning: Failed to extract Whisper model: %v", err)
 		} else {
 			log.Printf("Extracted embedded Whisper model: %s", targetPath)
 		}
 	} else {
-		log.Printf("No embedded Whisper model found - will use download fallback")
+		log.Printf("No Whisper model available in any asset store")
 	}
 	
 	// Extract Piper voice models
@@ -225,18 +407,18 @@ func (am *AssetManager) extractVoiceModels(ctx context.Context, info *PlatformIn
 		onnxPath := fmt.Sprintf("assets/models/piper/%s.onnx", voice)
 		jsonPath := fmt.Sprintf("assets/models/piper/%s.onnx.json", voice)
 		
-		if _, err := EmbeddedAssets.Open(onnxPath); err == nil {
+		if am.hasAsset(onnxPath) {
 			targetPath := filepath.Join(piperModelsDir, fmt.Sprintf("%s.onnx", voice))
-			if err := am.extractEmbeddedFile(onnxPath, targetPath); err != nil {
+			if err := am.extractEmbeddedFile(onnxPath, targetPath, "voices"); err != nil {
 				log.Printf("Warning: Failed to extract voice model %s: %v", voice, err)
 			} else {
 				log.Printf("Extracted voice model: %s", targetPath)
 			}
 		}
-		
-		if _, err := EmbeddedAssets.Open(jsonPath); err == nil {
+
+		if am.hasAsset(jsonPath) {
 			targetPath := filepath.Join(piperModelsDir, fmt.Sprintf("%s.onnx.json", voice))
-			if err := am.extractEmbeddedFile(jsonPath, targetPath); err != nil {
+			if err := am.extractEmbeddedFile(jsonPath, targetPath, "voices"); err != nil {
 				log.Printf("Warning: Failed to extract voice config %s: %v", voice, err)
 			} else {
 				log.Printf("Extracted voice config: %s", targetPath)
@@ -248,59 +430,69 @@ func (am *AssetManager) extractVoiceModels(ctx context.Context, info *PlatformIn
 }
 
 // extractEmbeddedZip extracts a ZIP archive from embedded assets
-func (am *AssetManager) extractEmbeddedZip(archivePath, targetDir string) error {
-	archiveData, err := EmbeddedAssets.ReadFile(archivePath)
+func (am *AssetManager) extractEmbeddedZip(archivePath, targetDir, service string) error {
+	archiveData, err := am.readAssetWithProgress(archivePath, service)
 	if err != nil {
-		return fmt.Errorf("failed to read embedded archive: %w", err)
+		return fmt.Errorf("failed to read archive: %w", err)
 	}
-	
+
 	// Create a zip reader from the embedded data
 	reader, err := zip.NewReader(strings.NewReader(string(archiveData)), int64(len(archiveData)))
 	if err != nil {
-		return fmt.Errorf("failed to create zip reader: %w", err)
+		return apierr.Wrap(apierr.ErrArchiveCorrupt, http.StatusInternalServerError, "failed to create zip reader", err)
 	}
-	
-	return am.extractZipFiles(reader, targetDir)
+
+	manifest, err := am.loadManifest(archivePath)
+	if err != nil {
+		return err
+	}
+
+	return am.extractZipFiles(reader, targetDir, manifest, service)
 }
 
 // extractEmbeddedTarGz extracts a TAR.GZ archive from embedded assets
-func (am *AssetManager) extractEmbeddedTarGz(archivePath, targetDir string) error {
-	archiveData, err := EmbeddedAssets.ReadFile(archivePath)
+func (am *AssetManager) extractEmbeddedTarGz(archivePath, targetDir, service string) error {
+	archiveData, err := am.readAssetWithProgress(archivePath, service)
 	if err != nil {
-		return fmt.Errorf("failed to read embedded archive: %w", err)
+		return fmt.Errorf("failed to read archive: %w", err)
 	}
-	
+
 	// Create gzip reader
 	gzReader, err := gzip.NewReader(strings.NewReader(string(archiveData)))
 	if err != nil {
-		return fmt.Errorf("failed to create gzip reader: %w", err)
+		return apierr.Wrap(apierr.ErrArchiveCorrupt, http.StatusInternalServerError, "failed to create gzip reader", err)
 	}
 	defer gzReader.Close()
-	
+
 	// Create tar reader
 	tarReader := tar.NewReader(gzReader)
-	
-	return am.extractTarFiles(tarReader, targetDir)
+
+	manifest, err := am.loadManifest(archivePath)
+	if err != nil {
+		return err
+	}
+
+	return am.extractTarFiles(tarReader, targetDir, manifest, service)
 }
 
 // extractEmbeddedFile extracts a single file from embedded assets
-func (am *AssetManager) extractEmbeddedFile(embeddedPath, targetPath string) error {
-	data, err := EmbeddedAssets.ReadFile(embeddedPath)
+func (am *AssetManager) extractEmbeddedFile(embeddedPath, targetPath, service string) error {
+	data, err := am.readAssetWithProgress(embeddedPath, service)
 	if err != nil {
-		return fmt.Errorf("failed to read embedded file: %w", err)
+		return fmt.Errorf("failed to read asset: %w", err)
 	}
-	
+
 	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
 		return fmt.Errorf("failed to create target directory: %w", err)
 	}
-	
+
 	return os.WriteFile(targetPath, data, 0644)
 }
 
 // extractZipFiles extracts files from a ZIP archive
-func (am *AssetManager) extractZipFiles(reader *zip.Reader, targetDir string) error {
+func (am *AssetManager) extractZipFiles(reader *zip.Reader, targetDir string, manifest *assetManifest, service string) error {
 	for _, file := range reader.File {
-		if err := am.extractZipFile(file, targetDir); err != nil {
+		if err := am.extractZipFile(file, targetDir, manifest, service); err != nil {
 			log.Printf("Warning: Failed to extract %s: %v", file.Name, err)
 		}
 	}
@@ -308,48 +500,62 @@ func (am *AssetManager) extractZipFiles(reader *zip.Reader, targetDir string) er
 }
 
 // extractZipFile extracts a single file from ZIP
-func (am *AssetManager) extractZipFile(file *zip.File, targetDir string) error {
-	// Determine target path, handling nested directories
-	targetPath := filepath.Join(targetDir, file.Name)
-	
+func (am *AssetManager) extractZipFile(file *zip.File, targetDir string, manifest *assetManifest, service string) error {
+	// Determine target path, handling nested directories. sanitizePath
+	// rejects a malicious entry like "../../etc/foo" trying to escape
+	// targetDir (Zip-Slip).
+	targetPath, err := sanitizePath(targetDir, file.Name)
+	if err != nil {
+		return err
+	}
+
 	// Handle directory entries
 	if file.FileInfo().IsDir() {
 		return os.MkdirAll(targetPath, file.FileInfo().Mode())
 	}
-	
+
 	// Create target directory
 	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
-	
+
 	// Extract file
 	rc, err := file.Open()
 	if err != nil {
 		return fmt.Errorf("failed to open file in archive: %w", err)
 	}
 	defer rc.Close()
-	
+
 	outFile, err := os.Create(targetPath)
 	if err != nil {
 		return fmt.Errorf("failed to create target file: %w", err)
 	}
 	defer outFile.Close()
-	
-	_, err = io.Copy(outFile, rc)
+
+	var src io.Reader = rc
+	if am.reporter != nil {
+		src = io.TeeReader(rc, &progressCounter{reporter: am.reporter, service: service, phase: "extract", file: file.Name, total: int64(file.UncompressedSize64)})
+	}
+
+	_, err = io.Copy(outFile, src)
 	if err != nil {
 		return fmt.Errorf("failed to copy file data: %w", err)
 	}
-	
+
 	// Set permissions
 	if err := os.Chmod(targetPath, file.FileInfo().Mode()); err != nil {
 		log.Printf("Warning: Failed to set permissions for %s: %v", targetPath, err)
 	}
-	
+
+	if err := am.verifyExtractedFile(targetPath, targetDir, manifest, service); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // extractTarFiles extracts files from a TAR archive
-func (am *AssetManager) extractTarFiles(reader *tar.Reader, targetDir string) error {
+func (am *AssetManager) extractTarFiles(reader *tar.Reader, targetDir string, manifest *assetManifest, service string) error {
 	for {
 		header, err := reader.Next()
 		if err == io.EOF {
@@ -358,8 +564,8 @@ func (am *AssetManager) extractTarFiles(reader *tar.Reader, targetDir string) er
 		if err != nil {
 			return fmt.Errorf("failed to read tar header: %w", err)
 		}
-		
-		if err := am.extractTarFile(reader, header, targetDir); err != nil {
+
+		if err := am.extractTarFile(reader, header, targetDir, manifest, service); err != nil {
 			log.Printf("Warning: Failed to extract %s: %v", header.Name, err)
 		}
 	}
@@ -367,9 +573,14 @@ func (am *AssetManager) extractTarFiles(reader *tar.Reader, targetDir string) er
 }
 
 // extractTarFile extracts a single file from TAR
-func (am *AssetManager) extractTarFile(reader *tar.Reader, header *tar.Header, targetDir string) error {
-	targetPath := filepath.Join(targetDir, header.Name)
-	
+func (am *AssetManager) extractTarFile(reader *tar.Reader, header *tar.Header, targetDir string, manifest *assetManifest, service string) error {
+	// sanitizePath rejects a malicious entry like "../../etc/foo" trying to
+	// escape targetDir (Zip-Slip).
+	targetPath, err := sanitizePath(targetDir, header.Name)
+	if err != nil {
+		return err
+	}
+
 	switch header.Typeflag {
 	case tar.TypeDir:
 		return os.MkdirAll(targetPath, os.FileMode(header.Mode))
@@ -378,25 +589,112 @@ func (am *AssetManager) extractTarFile(reader *tar.Reader, header *tar.Header, t
 		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
 			return fmt.Errorf("failed to create directory: %w", err)
 		}
-		
+
 		// Extract file
 		outFile, err := os.Create(targetPath)
 		if err != nil {
 			return fmt.Errorf("failed to create target file: %w", err)
 		}
 		defer outFile.Close()
-		
-		_, err = io.Copy(outFile, reader)
+
+		var src io.Reader = io.LimitReader(reader, header.Size)
+		if am.reporter != nil {
+			src = io.TeeReader(src, &progressCounter{reporter: am.reporter, service: service, phase: "extract", file: header.Name, total: header.Size})
+		}
+
+		_, err = io.Copy(outFile, src)
 		if err != nil {
 			return fmt.Errorf("failed to copy file data: %w", err)
 		}
-		
+
 		// Set permissions
 		if err := os.Chmod(targetPath, os.FileMode(header.Mode)); err != nil {
 			log.Printf("Warning: Failed to set permissions for %s: %v", targetPath, err)
 		}
+
+		if err := am.verifyExtractedFile(targetPath, targetDir, manifest, service); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sanitizePath joins targetDir and entryName the way a well-behaved archive
+// entry should, and rejects anything else: an absolute path, or a cleaned
+// name that resolves outside targetDir (the classic Zip-Slip path-traversal
+// attack via an entry like "../../etc/foo").
+func sanitizePath(targetDir, entryName string) (string, error) {
+	if filepath.IsAbs(entryName) {
+		return "", apierr.New(apierr.ErrArchiveCorrupt, http.StatusInternalServerError, fmt.Sprintf("archive entry has an absolute path: %s", entryName))
+	}
+
+	targetPath := filepath.Join(targetDir, filepath.Clean(entryName))
+
+	rel, err := filepath.Rel(targetDir, targetPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", apierr.New(apierr.ErrArchiveCorrupt, http.StatusInternalServerError, fmt.Sprintf("archive entry escapes target directory: %s", entryName))
+	}
+
+	return targetPath, nil
+}
+
+// verifyExtractedFile hashes a freshly extracted file and checks it against
+// the archive's manifest (if one was embedded). A mismatch means the
+// archive was corrupted or tampered with, so the file is quarantined into
+// bin.invalid/ rather than left on a path whisper/piper would load it from.
+// On success, the expected checksum is cached so VerifyAsset can re-check
+// the file later without the manifest.
+func (am *AssetManager) verifyExtractedFile(targetPath, targetDir string, manifest *assetManifest, service string) error {
+	if manifest == nil {
+		return nil
+	}
+
+	rel, err := filepath.Rel(targetDir, targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to compute manifest key for %s: %w", targetPath, err)
+	}
+	relKey := filepath.ToSlash(rel)
+
+	expected, ok := manifest.Files[relKey]
+	if !ok {
+		// Not every extracted entry needs to be listed (e.g. directories);
+		// absence from the manifest isn't itself an integrity failure.
+		return nil
+	}
+
+	if am.reporter != nil {
+		am.reporter.Report(ProgressEvent{Service: service, Phase: "verify", File: relKey})
+	}
+
+	actual, err := am.GetChecksum(targetPath)
+	if err != nil {
+		return apierr.Wrap(apierr.ErrAssetVerification, http.StatusInternalServerError, fmt.Sprintf("failed to checksum %s", relKey), err)
+	}
+	if actual != expected {
+		if qErr := am.quarantineInvalidAsset(targetPath, relKey); qErr != nil {
+			return apierr.Wrap(apierr.ErrAssetVerification, http.StatusInternalServerError, fmt.Sprintf("checksum mismatch for %s (and failed to quarantine)", relKey), qErr)
+		}
+		return apierr.New(apierr.ErrAssetVerification, http.StatusInternalServerError, fmt.Sprintf("checksum mismatch for %s: expected %s, got %s (quarantined)", relKey, expected, actual))
+	}
+
+	am.checksums[targetPath] = expected
+	return nil
+}
+
+// quarantineInvalidAsset moves a file that failed manifest verification out
+// of the live asset tree and into bin.invalid/, so a corrupted or tampered
+// binary/model can never end up on the execution path even if the warning
+// logged by the caller goes unnoticed.
+func (am *AssetManager) quarantineInvalidAsset(path, relKey string) error {
+	quarantinePath := filepath.Join(am.baseDir, "bin.invalid", relKey)
+
+	if err := os.MkdirAll(filepath.Dir(quarantinePath), 0755); err != nil {
+		return fmt.Errorf("failed to create quarantine directory: %w", err)
+	}
+	if err := os.Rename(path, quarantinePath); err != nil {
+		return fmt.Errorf("failed to quarantine invalid asset: %w", err)
 	}
-	
 	return nil
 }
 
@@ -455,18 +753,42 @@ func (am *AssetManager) IsAssetAvailable(assetPath string) bool {
 	return true
 }
 
-// GetChecksum returns MD5 checksum of a file for integrity verification
+// GetChecksum returns the SHA-256 checksum of a file for integrity verification
 func (am *AssetManager) GetChecksum(filePath string) (string, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return "", err
 	}
 	defer file.Close()
-	
-	hash := md5.New()
+
+	hash := sha256.New()
 	if _, err := io.Copy(hash, file); err != nil {
 		return "", err
 	}
-	
+
 	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// VerifyAsset re-hashes an already-extracted file and checks it against the
+// SHA-256 recorded when it was verified against its archive's manifest (see
+// verifyExtractedFile), catching tampering or disk corruption that happens
+// after extraction rather than only during it. Returns nil if the asset has
+// no recorded checksum - e.g. it wasn't covered by a manifest, or hasn't
+// been extracted by this AssetManager instance - since that's not itself
+// evidence the asset is invalid.
+func (am *AssetManager) VerifyAsset(path string) error {
+	expected, ok := am.checksums[path]
+	if !ok {
+		return nil
+	}
+
+	actual, err := am.GetChecksum(path)
+	if err != nil {
+		return fmt.Errorf("failed to checksum %s: %w", path, err)
+	}
+	if actual != expected {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", path, expected, actual)
+	}
+
+	return nil
 }
\ No newline at end of file
@@ -0,0 +1,79 @@
+package embedded
+
+import "io"
+
+// ProgressEvent is one structured update about a download, extraction, or
+// verification step that EnsureAssets is working through, so a caller (e.g.
+// the SSE handler in internal/api) can surface live progress instead of
+// waiting for EnsureAssets to return.
+type ProgressEvent struct {
+	Service string `json:"service,omitempty"`
+	Phase   string `json:"phase"` // "download", "extract", "verify", "done", "error"
+	File    string `json:"file,omitempty"`
+	Bytes   int64  `json:"bytes"`
+	Total   int64  `json:"total,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ProgressReporter receives the ProgressEvents EnsureAssets emits as it
+// resolves, extracts, and verifies assets. A nil ProgressReporter is valid
+// everywhere AssetManager holds one - every call site checks for nil before
+// reporting - so callers that don't care about progress don't need a no-op
+// implementation.
+type ProgressReporter interface {
+	Report(event ProgressEvent)
+}
+
+// progressCountingReader wraps a reader, reporting cumulative bytes read to
+// a ProgressReporter as the caller consumes it. It's used for the asset read
+// phase (embed/cache/mirror resolution in readAssetWithProgress); the archive
+// extraction phase (extractZipFile/extractTarFile) uses progressCounter.
+type progressCountingReader struct {
+	io.Reader
+	reporter ProgressReporter
+	service  string
+	phase    string
+	file     string
+	total    int64
+	read     int64
+}
+
+func (r *progressCountingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		r.read += int64(n)
+		r.reporter.Report(ProgressEvent{
+			Service: r.service,
+			Phase:   r.phase,
+			File:    r.file,
+			Bytes:   r.read,
+			Total:   r.total,
+		})
+	}
+	return n, err
+}
+
+// progressCounter is an io.Writer used as the destination of an
+// io.TeeReader wrapped around an archive entry's reader, so the bytes
+// extractZipFile/extractTarFile copy to disk are reported via
+// ProgressReporter without a second read pass over the entry.
+type progressCounter struct {
+	reporter ProgressReporter
+	service  string
+	phase    string
+	file     string
+	total    int64
+	written  int64
+}
+
+func (c *progressCounter) Write(p []byte) (int, error) {
+	c.written += int64(len(p))
+	c.reporter.Report(ProgressEvent{
+		Service: c.service,
+		Phase:   c.phase,
+		File:    c.file,
+		Bytes:   c.written,
+		Total:   c.total,
+	})
+	return len(p), nil
+}
@@ -0,0 +1,257 @@
+package embedded
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// AssetStore is a read-only source of asset files, identified by a
+// slash-separated path such as "assets/piper/piper_linux_amd64.tar.gz".
+// AssetManager tries a chain of stores in order until one has the file, so
+// the same extraction code handles assets baked into the binary, ones
+// cached on disk from a previous download, and ones fetched fresh from an
+// HTTP mirror.
+type AssetStore interface {
+	// Open returns a reader for path, or an error satisfying
+	// errors.Is(err, fs.ErrNotExist) if this store doesn't have it.
+	Open(path string) (fs.File, error)
+	// Stat returns file info for path without extracting it.
+	Stat(path string) (fs.FileInfo, error)
+	// List returns every path this store currently holds under prefix.
+	List(prefix string) ([]string, error)
+}
+
+// embedStore serves assets baked into the binary via go:embed.
+type embedStore struct {
+	fs embed.FS
+}
+
+func newEmbedStore(assets embed.FS) *embedStore {
+	return &embedStore{fs: assets}
+}
+
+func (s *embedStore) Open(path string) (fs.File, error) {
+	return s.fs.Open(path)
+}
+
+func (s *embedStore) Stat(path string) (fs.FileInfo, error) {
+	f, err := s.fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.Stat()
+}
+
+func (s *embedStore) List(prefix string) ([]string, error) {
+	var out []string
+	err := fs.WalkDir(s.fs, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasPrefix(path, prefix) {
+			out = append(out, path)
+		}
+		return nil
+	})
+	return out, err
+}
+
+// dirStore serves assets from a plain directory on disk, e.g. baseDir/cache
+// where previously downloaded archives are kept between runs.
+type dirStore struct {
+	root string
+}
+
+func newDirStore(root string) *dirStore {
+	return &dirStore{root: root}
+}
+
+func (s *dirStore) resolve(path string) string {
+	return filepath.Join(s.root, filepath.FromSlash(path))
+}
+
+func (s *dirStore) Open(path string) (fs.File, error) {
+	return os.Open(s.resolve(path))
+}
+
+func (s *dirStore) Stat(path string) (fs.FileInfo, error) {
+	return os.Stat(s.resolve(path))
+}
+
+func (s *dirStore) List(prefix string) ([]string, error) {
+	root := s.resolve(prefix)
+	var out []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			if rel, relErr := filepath.Rel(s.root, p); relErr == nil {
+				out = append(out, filepath.ToSlash(rel))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+// httpStore fetches assets on demand from an HTTP mirror (e.g. a GitHub
+// Releases download URL), caching each file under cacheDir so repeat runs
+// don't re-download. A partial download is resumed with a Range request on
+// the next attempt, and a fully cached file is re-validated against the
+// mirror's ETag rather than always treated as fresh.
+type httpStore struct {
+	baseURL  string
+	cacheDir string
+	client   *http.Client
+}
+
+func newHTTPStore(baseURL, cacheDir string) *httpStore {
+	return &httpStore{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		cacheDir: cacheDir,
+		client:   &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+func (s *httpStore) cachePath(path string) string {
+	return filepath.Join(s.cacheDir, filepath.FromSlash(path))
+}
+
+func (s *httpStore) etagPath(path string) string {
+	return s.cachePath(path) + ".etag"
+}
+
+// ensureCached downloads path into the cache if it isn't already there,
+// resuming a previous partial download via Range, and re-validates an
+// existing cached copy against the mirror's ETag before trusting it.
+func (s *httpStore) ensureCached(path string) (string, error) {
+	cachePath := s.cachePath(path)
+	url := s.baseURL + "/" + path
+
+	if info, err := os.Stat(cachePath); err == nil && info.Size() > 0 && !s.etagStale(path, url) {
+		return cachePath, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create asset cache directory: %w", err)
+	}
+
+	partialPath := cachePath + ".partial"
+	var startOffset int64
+	if info, err := os.Stat(partialPath); err == nil {
+		startOffset = info.Size()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build request for %s: %w", url, err)
+	}
+	if startOffset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", startOffset))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		flags |= os.O_APPEND
+	case http.StatusOK:
+		flags |= os.O_TRUNC
+		startOffset = 0
+	case http.StatusNotFound:
+		return "", fmt.Errorf("%s: %w", url, fs.ErrNotExist)
+	default:
+		return "", fmt.Errorf("unexpected status %d fetching %s", resp.StatusCode, url)
+	}
+
+	out, err := os.OpenFile(partialPath, flags, 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to open cache file for %s: %w", path, err)
+	}
+
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		out.Close()
+		return "", fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	if err := out.Close(); err != nil {
+		return "", fmt.Errorf("failed to finalize download of %s: %w", url, err)
+	}
+
+	if err := os.Rename(partialPath, cachePath); err != nil {
+		return "", fmt.Errorf("failed to finalize cached asset %s: %w", path, err)
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		_ = os.WriteFile(s.etagPath(path), []byte(etag), 0644)
+	}
+
+	return cachePath, nil
+}
+
+// etagStale reports whether the mirror's current ETag for url differs from
+// the one recorded alongside the cached copy of path. Any error talking to
+// the mirror is treated as "not stale" - if the network is unavailable, the
+// cached copy is better than nothing.
+func (s *httpStore) etagStale(path, url string) bool {
+	cachedETag, err := os.ReadFile(s.etagPath(path))
+	if err != nil {
+		return false
+	}
+
+	req, err := http.NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	etag := resp.Header.Get("ETag")
+	return etag != "" && etag != string(cachedETag)
+}
+
+func (s *httpStore) Open(path string) (fs.File, error) {
+	cachePath, err := s.ensureCached(path)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(cachePath)
+}
+
+func (s *httpStore) Stat(path string) (fs.FileInfo, error) {
+	cachePath, err := s.ensureCached(path)
+	if err != nil {
+		return nil, err
+	}
+	return os.Stat(cachePath)
+}
+
+// List is unsupported for an HTTP mirror: unlike embed.FS or a local
+// directory there's no generic listing API across the kinds of hosts this
+// targets (GitHub Releases assets, for instance, are enumerated through a
+// separate REST API, not a filesystem walk).
+func (s *httpStore) List(prefix string) ([]string, error) {
+	return nil, fmt.Errorf("httpStore does not support listing (prefix %q)", prefix)
+}
@@ -0,0 +1,233 @@
+// Package metrics collects request/inference-level Prometheus counters and
+// histograms, complementing the per-backend circuit-breaker/latency
+// tracking services.BackendMonitor already exposes on GET /metrics.
+// Like BackendMonitor, this hand-formats Prometheus text exposition rather
+// than pulling in a client library dependency for a handful of metric
+// families.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// durationBucketsSeconds are the histogram bucket upper bounds shared by
+// alice_request_duration_seconds and alice_model_inference_duration_seconds,
+// loosely modeled on Prometheus client_golang's own DefBuckets.
+var durationBucketsSeconds = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// histogram is a hand-rolled cumulative-bucket histogram, the minimum
+// needed to emit Prometheus's "le" bucket format without a client library.
+type histogram struct {
+	mu      sync.Mutex
+	buckets []uint64 // cumulative count of observations <= durationBucketsSeconds[i]
+	sum     float64
+	count   uint64
+}
+
+func newHistogram() *histogram {
+	return &histogram{buckets: make([]uint64, len(durationBucketsSeconds))}
+}
+
+func (h *histogram) observe(seconds float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sum += seconds
+	h.count++
+	for i, bound := range durationBucketsSeconds {
+		if seconds <= bound {
+			h.buckets[i]++
+		}
+	}
+}
+
+func (h *histogram) snapshot() (buckets []uint64, sum float64, count uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	buckets = make([]uint64, len(h.buckets))
+	copy(buckets, h.buckets)
+	return buckets, h.sum, h.count
+}
+
+// requestKey identifies one alice_requests_total series.
+type requestKey struct {
+	route, method, status string
+}
+
+// Recorder accumulates the metrics described in package metrics' doc
+// comment. A single instance is constructed alongside the rest of
+// models.Manager's infrastructure (see Manager.GetMetricsRecorder) and
+// shared across every request.
+type Recorder struct {
+	mu           sync.Mutex
+	requests     map[requestKey]uint64
+	requestDur   map[string]*histogram // keyed by "route method"
+	inferenceDur map[string]*histogram // keyed by service name
+
+	embeddingsQueueDepth int64 // atomic; see IncEmbeddingsQueueDepth
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		requests:     make(map[requestKey]uint64),
+		requestDur:   make(map[string]*histogram),
+		inferenceDur: make(map[string]*histogram),
+	}
+}
+
+// ObserveRequest records one completed HTTP request for alice_requests_total
+// and alice_request_duration_seconds. route should be the mux path template
+// (e.g. "/api/embeddings/generate"), not the raw URL, so templated segments
+// like {id} don't explode the series cardinality.
+func (r *Recorder) ObserveRequest(route, method string, status int, duration time.Duration) {
+	key := requestKey{route: route, method: method, status: strconv.Itoa(status)}
+	histKey := route + " " + method
+
+	r.mu.Lock()
+	r.requests[key]++
+	h, ok := r.requestDur[histKey]
+	if !ok {
+		h = newHistogram()
+		r.requestDur[histKey] = h
+	}
+	r.mu.Unlock()
+
+	h.observe(duration.Seconds())
+}
+
+// ObserveModelInference records one model call's duration for
+// alice_model_inference_duration_seconds{service}. This is narrower than
+// ObserveRequest's duration: it times only the service call (GenerateEmbedding,
+// Synthesize, Transcribe), not request decoding/encoding or middleware
+// overhead around it.
+func (r *Recorder) ObserveModelInference(service string, duration time.Duration) {
+	r.mu.Lock()
+	h, ok := r.inferenceDur[service]
+	if !ok {
+		h = newHistogram()
+		r.inferenceDur[service] = h
+	}
+	r.mu.Unlock()
+
+	h.observe(duration.Seconds())
+}
+
+// IncEmbeddingsQueueDepth and DecEmbeddingsQueueDepth track
+// alice_embeddings_queue_depth: the number of embedding requests currently
+// being served concurrently. There's no literal work queue in front of the
+// embeddings service today, so this doubles as that gauge - call Inc before
+// dispatching to the embedding service and defer Dec, the same way a real
+// queue's depth would rise on enqueue and fall on dequeue.
+func (r *Recorder) IncEmbeddingsQueueDepth() {
+	atomic.AddInt64(&r.embeddingsQueueDepth, 1)
+}
+
+func (r *Recorder) DecEmbeddingsQueueDepth() {
+	atomic.AddInt64(&r.embeddingsQueueDepth, -1)
+}
+
+// WritePrometheus appends this Recorder's metrics, in Prometheus text
+// exposition format, to w. Handler.Metrics calls this after
+// BackendMonitor.WritePrometheus so GET /metrics serves both sets of
+// metrics from the same response.
+func (r *Recorder) WritePrometheus(w io.Writer) {
+	r.mu.Lock()
+	requests := make(map[requestKey]uint64, len(r.requests))
+	for k, v := range r.requests {
+		requests[k] = v
+	}
+	requestDur := make(map[string]*histogram, len(r.requestDur))
+	for k, v := range r.requestDur {
+		requestDur[k] = v
+	}
+	inferenceDur := make(map[string]*histogram, len(r.inferenceDur))
+	for k, v := range r.inferenceDur {
+		inferenceDur[k] = v
+	}
+	r.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP alice_requests_total Total HTTP requests by route, method, and status code")
+	fmt.Fprintln(w, "# TYPE alice_requests_total counter")
+	keys := make([]requestKey, 0, len(requests))
+	for k := range requests {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		if keys[i].method != keys[j].method {
+			return keys[i].method < keys[j].method
+		}
+		return keys[i].status < keys[j].status
+	})
+	for _, k := range keys {
+		fmt.Fprintf(w, "alice_requests_total{route=%q,method=%q,status=%q} %d\n", k.route, k.method, k.status, requests[k])
+	}
+
+	fmt.Fprintln(w, "# HELP alice_request_duration_seconds HTTP request latency by route and method")
+	fmt.Fprintln(w, "# TYPE alice_request_duration_seconds histogram")
+	writeHistogram(w, "alice_request_duration_seconds", requestDur, "route", "method")
+
+	fmt.Fprintln(w, "# HELP alice_model_inference_duration_seconds Model backend call latency by service")
+	fmt.Fprintln(w, "# TYPE alice_model_inference_duration_seconds histogram")
+	writeHistogram(w, "alice_model_inference_duration_seconds", inferenceDur, "service")
+
+	fmt.Fprintln(w, "# HELP alice_embeddings_queue_depth Embedding requests currently being served concurrently")
+	fmt.Fprintln(w, "# TYPE alice_embeddings_queue_depth gauge")
+	fmt.Fprintf(w, "alice_embeddings_queue_depth %d\n", atomic.LoadInt64(&r.embeddingsQueueDepth))
+}
+
+// writeHistogram formats one histogram family. labelNames are the label
+// keys whose values are packed, space-separated, into each series' map key
+// (see requestDur/inferenceDur above) - "route method" splits back into two
+// labels, "service" stays one.
+func writeHistogram(w io.Writer, name string, series map[string]*histogram, labelNames ...string) {
+	keys := make([]string, 0, len(series))
+	for k := range series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		h := series[key]
+		buckets, sum, count := h.snapshot()
+		labelValues := splitLabels(key, len(labelNames))
+
+		for i, bound := range durationBucketsSeconds {
+			fmt.Fprintf(w, "%s_bucket{%s,le=%q} %d\n", name, labelPairs(labelNames, labelValues), formatBound(bound), buckets[i])
+		}
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labelPairs(labelNames, labelValues), count)
+		fmt.Fprintf(w, "%s_sum{%s} %g\n", name, labelPairs(labelNames, labelValues), sum)
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, labelPairs(labelNames, labelValues), count)
+	}
+}
+
+// splitLabels reverses the " "-joined packing ObserveRequest/
+// ObserveModelInference use for histogram map keys, back into n label
+// values ("a b" with n=2 -> ["a","b"]; "a" with n=1 -> ["a"]).
+func splitLabels(key string, n int) []string {
+	if n <= 1 {
+		return []string{key}
+	}
+	return strings.SplitN(key, " ", n)
+}
+
+func labelPairs(names, values []string) string {
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf("%s=%q", name, values[i])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func formatBound(bound float64) string {
+	return strconv.FormatFloat(bound, 'g', -1, 64)
+}
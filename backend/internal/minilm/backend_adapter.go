@@ -0,0 +1,66 @@
+package minilm
+
+import (
+	"context"
+	"fmt"
+
+	"alice-backend/pkg/grpc/base"
+)
+
+// BackendAdapter lets an EmbeddingProvider register as a pkg/grpc/base
+// Backend without changing EmbeddingService/OnnxEmbeddingService
+// themselves, so either can be run as a supervised out-of-process worker
+// (see cmd/minilm-worker) instead of loading the ONNX Runtime directly into
+// the API server. It only implements Embed/Health/LoadModel for real -
+// Predict/TranscribeAudio/SynthesizeSpeech don't apply to an embeddings
+// provider, so they return a "not supported" error rather than being
+// omitted, since net/rpc requires every Backend method on the registered
+// receiver.
+type BackendAdapter struct {
+	provider EmbeddingProvider
+}
+
+// NewBackendAdapter wraps provider for registration with base.NewServer.
+func NewBackendAdapter(provider EmbeddingProvider) *BackendAdapter {
+	return &BackendAdapter{provider: provider}
+}
+
+func (a *BackendAdapter) Health(req base.HealthRequest, resp *base.HealthResponse) error {
+	resp.Ready = a.provider.IsReady()
+	if resp.Ready {
+		resp.Status = "ready"
+	} else {
+		resp.Status = "not ready"
+	}
+	return nil
+}
+
+// LoadModel re-initializes the wrapped provider. ModelPath/Params are
+// ignored: EmbeddingProvider.Initialize takes no arguments, configuring the
+// model path through Config at construction time instead.
+func (a *BackendAdapter) LoadModel(req base.LoadModelRequest, resp *base.LoadModelResponse) error {
+	return a.provider.Initialize(context.Background())
+}
+
+func (a *BackendAdapter) Embed(req base.EmbedRequest, resp *base.EmbedResponse) error {
+	vectors, err := a.provider.GenerateEmbeddings(context.Background(), req.Texts)
+	if err != nil {
+		return err
+	}
+	resp.Vectors = vectors
+	return nil
+}
+
+func (a *BackendAdapter) Predict(req base.PredictRequest, resp *base.PredictResponse) error {
+	return fmt.Errorf("Predict is not supported by the minilm backend")
+}
+
+func (a *BackendAdapter) TranscribeAudio(req base.TranscribeRequest, resp *base.TranscribeResponse) error {
+	return fmt.Errorf("TranscribeAudio is not supported by the minilm backend")
+}
+
+func (a *BackendAdapter) SynthesizeSpeech(req base.SynthesizeRequest, resp *base.SynthesizeResponse) error {
+	return fmt.Errorf("SynthesizeSpeech is not supported by the minilm backend")
+}
+
+var _ base.Backend = (*BackendAdapter)(nil)
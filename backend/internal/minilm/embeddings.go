@@ -3,208 +3,120 @@ package minilm
 import (
 	"context"
 	"fmt"
-	"log"
-	"sync"
-	"time"
-)
-
-// Config holds embeddings configuration
-type Config struct {
-	ModelPath string
-	Dimension int
-}
+	"log/slog"
+	"path/filepath"
 
-// ServiceInfo contains information about the embeddings service
-type ServiceInfo struct {
-	Name        string            `json:"name"`
-	Version     string            `json:"version"`
-	Status      string            `json:"status"`
-	Model       string            `json:"model"`
-	Dimension   int               `json:"dimension"`
-	LastUpdated time.Time         `json:"last_updated"`
-	Metadata    map[string]string `json:"metadata"`
-}
+	"alice-backend/internal/downloader"
+)
 
-// EmbeddingService provides text embedding functionality using MiniLM
+// EmbeddingService provides text embedding functionality using MiniLM. It's
+// a thin wrapper around OnnxEmbeddingService (tokenizer, session pool,
+// mean-pooling/L2-normalize inference - see onnx_embeddings.go) that adds
+// the ModelRegistry-based download/select bookkeeping the rest of the
+// codebase (models.Manager, the /api/models endpoints) expects from an
+// embeddings service, mirroring how piper.TTSService layers catalog
+// download/select logic on top of its own synthesis engine.
 type EmbeddingService struct {
-	mu     sync.RWMutex
-	ready  bool
-	config *Config
-	info   *ServiceInfo
+	config    *Config
+	onnx      *OnnxEmbeddingService
+	downloads *downloader.Manager
 }
 
 // NewEmbeddingService creates a new embedding service
 func NewEmbeddingService(config *Config) *EmbeddingService {
 	return &EmbeddingService{
-		config: config,
-		info: &ServiceInfo{
-			Name:        "MiniLM Embeddings",
-			Version:     "1.0.0",
-			Status:      "initializing",
-			Model:       "all-MiniLM-L6-v2",
-			Dimension:   config.Dimension,
-			LastUpdated: time.Now(),
-			Metadata:    make(map[string]string),
-		},
+		config:    config,
+		onnx:      NewOnnxEmbeddingService(config),
+		downloads: downloader.NewManager(),
 	}
 }
 
-// Initialize initializes the embeddings service
+// Initialize loads the ONNX Runtime shared library, the all-MiniLM-L6-v2
+// model, and its WordPiece tokenizer (see OnnxEmbeddingService.Initialize),
+// and downloads any of it that isn't already cached on disk.
 func (s *EmbeddingService) Initialize(ctx context.Context) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	slog.Info("Initializing embeddings service")
 
-	log.Println("Initializing embeddings service...")
-
-	s.ready = true
-	s.info.Status = "ready"
-	s.info.LastUpdated = time.Now()
+	if err := s.onnx.Initialize(ctx); err != nil {
+		return err
+	}
 
-	log.Println("Embeddings service initialized successfully")
+	slog.Info("Embeddings service initialized successfully")
 	return nil
 }
 
 // IsReady returns true if the service is ready
 func (s *EmbeddingService) IsReady() bool {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	return s.ready
+	return s.onnx.IsReady()
 }
 
 // GetInfo returns service information
 func (s *EmbeddingService) GetInfo() *ServiceInfo {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	info := *s.info
-	info.LastUpdated = time.Now()
-	return &info
+	return s.onnx.GetInfo()
 }
 
-// GenerateEmbedding generates a single embedding
+// GenerateEmbedding tokenizes text with WordPiece, runs it through the
+// MiniLM ONNX session, mean-pools the token embeddings (weighted by the
+// attention mask) and L2-normalizes the result.
 func (s *EmbeddingService) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
-	if !s.IsReady() {
-		return nil, fmt.Errorf("embeddings service is not ready")
-	}
-
 	if text == "" {
 		return nil, fmt.Errorf("text cannot be empty")
 	}
-
-	// In a real implementation, this would use the MiniLM model
-	// For now, return a placeholder embedding
-	embedding := make([]float32, s.config.Dimension)
-	for i := range embedding {
-		embedding[i] = float32(i) * 0.1 // Placeholder values
-	}
-	return embedding, nil
+	return s.onnx.GenerateEmbedding(ctx, text)
 }
 
-// GenerateEmbeddings generates multiple embeddings
+// GenerateEmbeddings generates multiple embeddings, reusing the service's
+// pooled ONNX sessions across concurrent requests.
 func (s *EmbeddingService) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
-	if !s.IsReady() {
-		return nil, fmt.Errorf("embeddings service is not ready")
-	}
-
 	if len(texts) == 0 {
 		return nil, fmt.Errorf("texts cannot be empty")
 	}
-
-	embeddings := make([][]float32, len(texts))
-	for i, text := range texts {
-		embedding, err := s.GenerateEmbedding(ctx, text)
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate embedding for text %d: %w", i, err)
-		}
-		embeddings[i] = embedding
-	}
-	return embeddings, nil
+	return s.onnx.GenerateEmbeddings(ctx, texts)
 }
 
 // ComputeSimilarity computes cosine similarity between two embeddings
 func (s *EmbeddingService) ComputeSimilarity(ctx context.Context, embedding1, embedding2 []float32) (float32, error) {
-	if len(embedding1) != len(embedding2) {
-		return 0, fmt.Errorf("embeddings must have the same dimension")
-	}
-
-	if len(embedding1) == 0 {
-		return 0, fmt.Errorf("embeddings cannot be empty")
-	}
-
-	// Compute dot product
-	dotProduct := float32(0)
-	for i := range embedding1 {
-		dotProduct += embedding1[i] * embedding2[i]
-	}
-
-	// Compute magnitudes
-	magnitude1 := float32(0)
-	magnitude2 := float32(0)
-	for i := range embedding1 {
-		magnitude1 += embedding1[i] * embedding1[i]
-		magnitude2 += embedding2[i] * embedding2[i]
-	}
-
-	if magnitude1 == 0 || magnitude2 == 0 {
-		return 0, nil
-	}
-
-	return dotProduct / (magnitude1 * magnitude2), nil
+	return s.onnx.ComputeSimilarity(ctx, embedding1, embedding2)
 }
 
 // SearchSimilar finds similar embeddings
 func (s *EmbeddingService) SearchSimilar(ctx context.Context, queryEmbedding []float32, candidateEmbeddings [][]float32, topK int) ([]int, []float32, error) {
-	if len(queryEmbedding) == 0 {
-		return nil, nil, fmt.Errorf("query embedding cannot be empty")
-	}
-
-	if len(candidateEmbeddings) == 0 {
-		return nil, nil, fmt.Errorf("candidate embeddings cannot be empty")
-	}
+	return s.onnx.SearchSimilar(ctx, queryEmbedding, candidateEmbeddings, topK)
+}
 
-	if topK <= 0 {
-		topK = 5
+// DownloadModel launches (or returns the already-running) background
+// download job for a ModelRegistry entry, verifying its SHA-256 digest
+// before it's renamed into place.
+func (s *EmbeddingService) DownloadModel(ctx context.Context, name string) (*downloader.Job, error) {
+	entry, ok := ModelRegistry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown embeddings model: %s", name)
 	}
 
-	// Compute similarities
-	similarities := make([]float32, len(candidateEmbeddings))
-	indices := make([]int, len(candidateEmbeddings))
-
-	for i, candidate := range candidateEmbeddings {
-		similarity, err := s.ComputeSimilarity(ctx, queryEmbedding, candidate)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to compute similarity for candidate %d: %w", i, err)
-		}
-		similarities[i] = similarity
-		indices[i] = i
-	}
+	dest := filepath.Join("models", "minilm", name+".onnx")
+	return s.downloads.Start(name, entry.URL, dest, entry.SHA256), nil
+}
 
-	// Sort by similarity (descending)
-	for i := 0; i < len(similarities)-1; i++ {
-		for j := i + 1; j < len(similarities); j++ {
-			if similarities[i] < similarities[j] {
-				similarities[i], similarities[j] = similarities[j], similarities[i]
-				indices[i], indices[j] = indices[j], indices[i]
-			}
-		}
-	}
+// ModelDownloadStatus returns the tracked download job for a model, if one
+// has been started.
+func (s *EmbeddingService) ModelDownloadStatus(name string) (*downloader.Job, bool) {
+	return s.downloads.Status(name)
+}
 
-	// Return top K
-	if topK > len(similarities) {
-		topK = len(similarities)
+// SelectModel validates name against ModelRegistry. ModelRegistry currently
+// only lists the one model Initialize always loads (all-MiniLM-L6-v2), so
+// this doesn't hot-swap anything yet; it exists so the generic
+// /api/models/{service}/select endpoint has something to validate against
+// once a second model is added to the registry.
+func (s *EmbeddingService) SelectModel(name string) error {
+	if _, ok := ModelRegistry[name]; !ok {
+		return fmt.Errorf("unknown embeddings model: %s", name)
 	}
-
-	return indices[:topK], similarities[:topK], nil
+	slog.Info("Embeddings active model confirmed", "model", name)
+	return nil
 }
 
 // Shutdown gracefully shuts down the embeddings service
 func (s *EmbeddingService) Shutdown(ctx context.Context) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	s.ready = false
-	s.info.Status = "stopped"
-	s.info.LastUpdated = time.Now()
-
-	return nil
+	return s.onnx.Shutdown(ctx)
 }
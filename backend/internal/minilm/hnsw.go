@@ -0,0 +1,432 @@
+package minilm
+
+import (
+	"container/heap"
+	"encoding/gob"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	hnswDefaultM              = 16
+	hnswDefaultEfConstruction = 200
+	hnswDefaultEfSearch       = 64
+)
+
+// Hit is a single ranked result from HNSWIndex.Search, identified by the
+// external id it was added under.
+type Hit struct {
+	ID    string
+	Score float32
+}
+
+// hnswNode is a graph node's per-layer adjacency list. Neighbors[l] holds
+// the internal ids of l's neighbors; a node only has entries up to its own
+// assigned Layer.
+type hnswNode struct {
+	Layer     int
+	Neighbors [][]int
+}
+
+// HNSWIndex is an approximate nearest-neighbor index over L2-normalized
+// vectors, built as a Hierarchical Navigable Small World graph (Malkov &
+// Yashunin). It replaces SearchSimilar's O(n log k) brute-force scan for large
+// candidate sets. Vectors live in a contiguous []float32 arena keyed by
+// internal id for cache-friendly dot products; cosine similarity reduces to
+// a plain dot product since every vector this module produces is already
+// L2-normalized.
+type HNSWIndex struct {
+	mu sync.RWMutex
+
+	dim            int
+	m              int
+	efConstruction int
+	efSearch       int
+	mL             float64
+	rng            *rand.Rand
+
+	arena        []float32
+	ids          []string
+	idToInternal map[string]int
+	nodes        []*hnswNode
+	entryPoint   int
+	maxLayer     int
+}
+
+// NewHNSWIndex creates an empty index over vectors of the given dimension,
+// using the M ≈ 16 / efConstruction ≈ 200 defaults recommended by the HNSW
+// paper.
+func NewHNSWIndex(dim int) *HNSWIndex {
+	m := hnswDefaultM
+	return &HNSWIndex{
+		dim:            dim,
+		m:              m,
+		efConstruction: hnswDefaultEfConstruction,
+		efSearch:       hnswDefaultEfSearch,
+		mL:             1 / math.Log(float64(m)),
+		rng:            rand.New(rand.NewSource(time.Now().UnixNano())),
+		idToInternal:   make(map[string]int),
+		entryPoint:     -1,
+		maxLayer:       -1,
+	}
+}
+
+// Len returns the number of vectors currently in the index.
+func (h *HNSWIndex) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.nodes)
+}
+
+// Add inserts vec under id. Re-adding an id already present adds a second,
+// independent node rather than updating the first; callers that need
+// updates should build a fresh index instead.
+func (h *HNSWIndex) Add(id string, vec []float32) error {
+	if len(vec) != h.dim {
+		return fmt.Errorf("vector has dimension %d, expected %d", len(vec), h.dim)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	internalID := len(h.nodes)
+	h.arena = append(h.arena, vec...)
+	h.ids = append(h.ids, id)
+	h.idToInternal[id] = internalID
+
+	level := h.randomLevel()
+	node := &hnswNode{Layer: level, Neighbors: make([][]int, level+1)}
+	h.nodes = append(h.nodes, node)
+
+	if internalID == 0 {
+		h.entryPoint = internalID
+		h.maxLayer = level
+		return nil
+	}
+
+	// Greedily descend from the top layer to one above this node's layer,
+	// tracking the single nearest node found as the entry point for the
+	// next layer down.
+	ep := h.entryPoint
+	for lc := h.maxLayer; lc > level; lc-- {
+		if nearest := h.searchLayer(vec, []int{ep}, 1, lc); len(nearest) > 0 {
+			ep = nearest[0].id
+		}
+	}
+
+	entryPoints := []int{ep}
+	for lc := min(level, h.maxLayer); lc >= 0; lc-- {
+		candidates := h.searchLayer(vec, entryPoints, h.efConstruction, lc)
+
+		maxConn := h.m
+		if lc == 0 {
+			maxConn = 2 * h.m
+		}
+
+		neighbors := selectNeighborsHeuristic(candidates, maxConn, h.distanceBetween)
+		node.Neighbors[lc] = append(node.Neighbors[lc], neighbors...)
+
+		for _, nb := range neighbors {
+			h.nodes[nb].Neighbors[lc] = append(h.nodes[nb].Neighbors[lc], internalID)
+			if len(h.nodes[nb].Neighbors[lc]) > maxConn {
+				h.pruneNeighbors(nb, lc, maxConn)
+			}
+		}
+
+		if len(candidates) > 0 {
+			entryPoints = []int{candidates[0].id}
+		}
+	}
+
+	if level > h.maxLayer {
+		h.entryPoint = internalID
+		h.maxLayer = level
+	}
+	return nil
+}
+
+// Search returns the k approximate nearest neighbors of q.
+func (h *HNSWIndex) Search(q []float32, k int) ([]Hit, error) {
+	if len(q) != h.dim {
+		return nil, fmt.Errorf("query has dimension %d, expected %d", len(q), h.dim)
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if len(h.nodes) == 0 {
+		return nil, nil
+	}
+
+	ef := h.efSearch
+	if k > ef {
+		ef = k
+	}
+
+	ep := h.entryPoint
+	for lc := h.maxLayer; lc > 0; lc-- {
+		if nearest := h.searchLayer(q, []int{ep}, 1, lc); len(nearest) > 0 {
+			ep = nearest[0].id
+		}
+	}
+
+	candidates := h.searchLayer(q, []int{ep}, ef, 0)
+	if k > len(candidates) {
+		k = len(candidates)
+	}
+
+	hits := make([]Hit, k)
+	for i := 0; i < k; i++ {
+		hits[i] = Hit{ID: h.ids[candidates[i].id], Score: 1 - candidates[i].dist}
+	}
+	return hits, nil
+}
+
+// randomLevel draws a node's layer from the geometric distribution HNSW
+// uses to keep the graph's top layers sparse: floor(-ln(U(0,1)) * mL).
+func (h *HNSWIndex) randomLevel() int {
+	u := h.rng.Float64()
+	for u <= 0 {
+		u = h.rng.Float64()
+	}
+	return int(math.Floor(-math.Log(u) * h.mL))
+}
+
+func (h *HNSWIndex) vectorAt(id int) []float32 {
+	return h.arena[id*h.dim : (id+1)*h.dim]
+}
+
+// distance is 1 minus cosine similarity (a plain dot product here, since
+// every vector is pre-normalized), so smaller means closer.
+func (h *HNSWIndex) distance(q []float32, id int) float32 {
+	return 1 - dotProduct(q, h.vectorAt(id))
+}
+
+func (h *HNSWIndex) distanceBetween(a, b int) float32 {
+	return 1 - dotProduct(h.vectorAt(a), h.vectorAt(b))
+}
+
+func dotProduct(a, b []float32) float32 {
+	var sum float32
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+// pruneNeighbors re-runs the neighbor-selection heuristic over nodeID's
+// existing connections at layer, keeping at most maxConn, after an
+// insertion pushed it over its out-degree cap.
+func (h *HNSWIndex) pruneNeighbors(nodeID, layer, maxConn int) {
+	node := h.nodes[nodeID]
+	existing := node.Neighbors[layer]
+
+	candidates := make([]hnswCandidate, len(existing))
+	for i, nb := range existing {
+		candidates[i] = hnswCandidate{dist: h.distanceBetween(nodeID, nb), id: nb}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].dist < candidates[j].dist })
+
+	node.Neighbors[layer] = selectNeighborsHeuristic(candidates, maxConn, h.distanceBetween)
+}
+
+// hnswCandidate pairs an internal node id with its distance to whatever
+// query vector produced it (an insertion vector during construction, or a
+// search query).
+type hnswCandidate struct {
+	dist float32
+	id   int
+}
+
+// selectNeighborsHeuristic picks up to maxConn candidates (sorted nearest
+// first) that are diverse with respect to each other: a candidate is kept
+// only if no already-selected neighbor is closer to it than it is to the
+// query, which avoids clustering all edges on one side of a dense region.
+func selectNeighborsHeuristic(candidates []hnswCandidate, maxConn int, distanceBetween func(a, b int) float32) []int {
+	selected := make([]int, 0, maxConn)
+	for _, cand := range candidates {
+		if len(selected) >= maxConn {
+			break
+		}
+		keep := true
+		for _, s := range selected {
+			if distanceBetween(s, cand.id) < cand.dist {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			selected = append(selected, cand.id)
+		}
+	}
+	return selected
+}
+
+// searchLayer performs the beam search at a single layer that underlies
+// both insertion and querying: a min-heap of candidates to explore, and a
+// bounded max-heap of the best ef results found so far. Returns results
+// sorted nearest first.
+func (h *HNSWIndex) searchLayer(q []float32, entryPoints []int, ef int, layer int) []hnswCandidate {
+	visited := make(map[int]bool, ef*2)
+	candidates := &candidateMinHeap{}
+	results := &candidateMaxHeap{}
+
+	for _, ep := range entryPoints {
+		d := h.distance(q, ep)
+		visited[ep] = true
+		heap.Push(candidates, hnswCandidate{dist: d, id: ep})
+		heap.Push(results, hnswCandidate{dist: d, id: ep})
+	}
+
+	for candidates.Len() > 0 {
+		c := heap.Pop(candidates).(hnswCandidate)
+		if results.Len() >= ef && c.dist > (*results)[0].dist {
+			break
+		}
+
+		// layer may exceed this node's own assigned layer if it was only
+		// ever reached as an entry point; such nodes have no edges here.
+		if layer >= len(h.nodes[c.id].Neighbors) {
+			continue
+		}
+
+		for _, nb := range h.nodes[c.id].Neighbors[layer] {
+			if visited[nb] {
+				continue
+			}
+			visited[nb] = true
+
+			d := h.distance(q, nb)
+			if results.Len() < ef || d < (*results)[0].dist {
+				heap.Push(candidates, hnswCandidate{dist: d, id: nb})
+				heap.Push(results, hnswCandidate{dist: d, id: nb})
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+
+	out := make([]hnswCandidate, results.Len())
+	copy(out, *results)
+	sort.Slice(out, func(i, j int) bool { return out[i].dist < out[j].dist })
+	return out
+}
+
+type candidateMinHeap []hnswCandidate
+
+func (h candidateMinHeap) Len() int            { return len(h) }
+func (h candidateMinHeap) Less(i, j int) bool   { return h[i].dist < h[j].dist }
+func (h candidateMinHeap) Swap(i, j int)        { h[i], h[j] = h[j], h[i] }
+func (h *candidateMinHeap) Push(x interface{})  { *h = append(*h, x.(hnswCandidate)) }
+func (h *candidateMinHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+type candidateMaxHeap []hnswCandidate
+
+func (h candidateMaxHeap) Len() int           { return len(h) }
+func (h candidateMaxHeap) Less(i, j int) bool { return h[i].dist > h[j].dist }
+func (h candidateMaxHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *candidateMaxHeap) Push(x interface{}) { *h = append(*h, x.(hnswCandidate)) }
+func (h *candidateMaxHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// hnswSnapshot is the gob-serializable form of an HNSWIndex, since the
+// index's own fields are unexported.
+type hnswSnapshot struct {
+	Dim            int
+	M              int
+	EfConstruction int
+	EfSearch       int
+	MLevel         float64
+	Arena          []float32
+	IDs            []string
+	NodeLayers     []int
+	NodeNeighbors  [][][]int
+	EntryPoint     int
+	MaxLayer       int
+}
+
+// Save persists the index to path via gob, so it can be reloaded across
+// restarts instead of being rebuilt from scratch.
+func (h *HNSWIndex) Save(path string) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	snap := hnswSnapshot{
+		Dim:            h.dim,
+		M:              h.m,
+		EfConstruction: h.efConstruction,
+		EfSearch:       h.efSearch,
+		MLevel:         h.mL,
+		Arena:          h.arena,
+		IDs:            h.ids,
+		EntryPoint:     h.entryPoint,
+		MaxLayer:       h.maxLayer,
+		NodeLayers:     make([]int, len(h.nodes)),
+		NodeNeighbors:  make([][][]int, len(h.nodes)),
+	}
+	for i, n := range h.nodes {
+		snap.NodeLayers[i] = n.Layer
+		snap.NodeNeighbors[i] = n.Neighbors
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return gob.NewEncoder(f).Encode(snap)
+}
+
+// Load replaces the index's contents with a snapshot previously written by
+// Save.
+func (h *HNSWIndex) Load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var snap hnswSnapshot
+	if err := gob.NewDecoder(f).Decode(&snap); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.dim = snap.Dim
+	h.m = snap.M
+	h.efConstruction = snap.EfConstruction
+	h.efSearch = snap.EfSearch
+	h.mL = snap.MLevel
+	h.arena = snap.Arena
+	h.ids = snap.IDs
+	h.entryPoint = snap.EntryPoint
+	h.maxLayer = snap.MaxLayer
+
+	h.nodes = make([]*hnswNode, len(snap.IDs))
+	h.idToInternal = make(map[string]int, len(snap.IDs))
+	for i, id := range snap.IDs {
+		h.nodes[i] = &hnswNode{Layer: snap.NodeLayers[i], Neighbors: snap.NodeNeighbors[i]}
+		h.idToInternal[id] = i
+	}
+	return nil
+}
@@ -4,23 +4,29 @@ import (
 	"archive/tar"
 	"archive/zip"
 	"compress/gzip"
+	"container/heap"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"math"
 	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 	"unicode"
 
 	ort "github.com/yalue/onnxruntime_go"
+	"golang.org/x/text/unicode/norm"
 )
 
 // OnnxEmbeddingService provides text embedding functionality using ONNX Runtime with pure Go tokenizer
@@ -30,8 +36,11 @@ type OnnxEmbeddingService struct {
 	config    *Config
 	info      *ServiceInfo
 	tokenizer *wordPiece
-	session   *ort.DynamicAdvancedSession
+	pool      *sessionPool
+	modelFile string
 	maxLen    int
+	index     *HNSWIndex
+	provider  ExecutionProvider
 }
 
 // Ensure OnnxEmbeddingService implements EmbeddingProvider
@@ -59,10 +68,10 @@ func (s *OnnxEmbeddingService) Initialize(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	log.Println("Initializing ONNX embeddings service with pure Go tokenizer...")
+	slog.Info("Initializing ONNX embeddings service with pure Go tokenizer")
 
 	// Ensure runtime and model files
-	if err := s.ensureRuntimeAndModel(); err != nil {
+	if err := s.ensureRuntimeAndModel(ctx); err != nil {
 		return fmt.Errorf("failed to ensure runtime and model: %w", err)
 	}
 
@@ -77,30 +86,41 @@ func (s *OnnxEmbeddingService) Initialize(ctx context.Context) error {
 	s.info.Metadata["onnx_runtime"] = "enabled"
 	s.info.Metadata["tokenizer"] = "pure_go_wordpiece"
 
-	log.Println("ONNX embeddings service initialized successfully")
+	slog.Info("ONNX embeddings service initialized successfully")
 	return nil
 }
 
-func (s *OnnxEmbeddingService) ensureRuntimeAndModel() error {
+func (s *OnnxEmbeddingService) ensureRuntimeAndModel(ctx context.Context) error {
 	// Ensure model directory
 	if err := os.MkdirAll(s.config.ModelPath, 0o755); err != nil {
 		return err
 	}
 
-	// Download ORT shared library
-	libPath, err := ensureORTSharedLib()
+	requested := s.config.ExecutionProvider
+	if requested == "" {
+		requested = ExecutionProviderAuto
+	}
+	if requested == ExecutionProviderAuto {
+		requested = resolveAutoExecutionProvider()
+	}
+
+	// Download ORT shared library, degrading to the CPU build if the
+	// accelerated archive for the requested provider isn't available.
+	libPath, provider, err := ensureORTSharedLib(ctx, requested)
 	if err != nil {
 		return fmt.Errorf("onnxruntime lib: %w", err)
 	}
+	s.provider = provider
 
 	// Point onnxruntime_go to the shared library
 	ort.SetSharedLibraryPath(libPath)
 
 	// Download model and vocab
-	_, vocabPath, err := ensureMiniLMModel(s.config.ModelPath)
+	modelPath, vocabPath, err := ensureMiniLMModel(ctx, s.config.ModelPath, s.config.Quantization)
 	if err != nil {
 		return err
 	}
+	s.modelFile = modelPath
 
 	// Load vocab-based WordPiece tokenizer (uncased)
 	tk, err := loadWordPiece(vocabPath)
@@ -111,6 +131,22 @@ func (s *OnnxEmbeddingService) ensureRuntimeAndModel() error {
 	return nil
 }
 
+// defaultSessionPoolSize returns runtime.NumCPU()/2, floored at 1, so a
+// single-core sandbox still gets a usable pool.
+func defaultSessionPoolSize() int {
+	n := runtime.NumCPU() / 2
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// initSession loads s.modelFile into a pool of independent
+// DynamicAdvancedSession handles so concurrent GenerateEmbeddings calls
+// aren't serialized behind a single session. The first session's
+// construction determines the actual execution provider (falling back to
+// CPU if the requested one fails to initialize); the rest reuse whatever
+// that resolved to.
 func (s *OnnxEmbeddingService) initSession() error {
 	if err := ort.InitializeEnvironment(); err != nil {
 		return err
@@ -120,15 +156,166 @@ func (s *OnnxEmbeddingService) initSession() error {
 	inNames := []string{"input_ids", "attention_mask", "token_type_ids"}
 	outNames := []string{"last_hidden_state"}
 
-	modelPath := filepath.Join(s.config.ModelPath, "model.onnx")
-	sess, err := ort.NewDynamicAdvancedSession(modelPath, inNames, outNames, nil)
-	if err != nil {
-		return err
+	poolSize := s.config.SessionPoolSize
+	if poolSize <= 0 {
+		poolSize = defaultSessionPoolSize()
+	}
+
+	sessions := make([]*ort.DynamicAdvancedSession, 0, poolSize)
+	for i := 0; i < poolSize; i++ {
+		options, actual := s.buildSessionOptions()
+		sess, err := ort.NewDynamicAdvancedSession(s.modelFile, inNames, outNames, options)
+		if err != nil && options != nil {
+			// The requested provider failed to initialize (e.g. no
+			// compatible GPU present) - fall back to plain CPU execution
+			// rather than failing the whole service.
+			slog.Warn("onnx: execution provider failed, falling back to cpu", "provider", s.provider, "error", err)
+			options.Destroy()
+			options = nil
+			actual = ExecutionProviderCPU
+			sess, err = ort.NewDynamicAdvancedSession(s.modelFile, inNames, outNames, nil)
+		}
+		if options != nil {
+			options.Destroy()
+		}
+		if err != nil {
+			for _, created := range sessions {
+				created.Destroy()
+			}
+			return err
+		}
+		s.provider = actual
+		sessions = append(sessions, sess)
 	}
-	s.session = sess
+
+	s.info.Metadata["execution_provider"] = string(s.provider)
+	s.info.Metadata["session_pool_size"] = strconv.Itoa(len(sessions))
+	s.info.Metadata["quantization"] = string(s.config.Quantization)
+	s.pool = newSessionPool(sessions)
 	return nil
 }
 
+// buildSessionOptions constructs ort.SessionOptions for s.provider, returning
+// (nil, ExecutionProviderCPU) when CPU was requested or no accelerated
+// provider applies, so the caller can fall back to the simple nil-options
+// session in either case.
+func (s *OnnxEmbeddingService) buildSessionOptions() (*ort.SessionOptions, ExecutionProvider) {
+	if s.provider == "" || s.provider == ExecutionProviderCPU {
+		return nil, ExecutionProviderCPU
+	}
+
+	options, err := ort.NewSessionOptions()
+	if err != nil {
+		slog.Warn("onnx: failed to create session options, using cpu", "error", err)
+		return nil, ExecutionProviderCPU
+	}
+
+	switch s.provider {
+	case ExecutionProviderCUDA:
+		cudaOptions, cErr := ort.NewCUDAProviderOptions()
+		if cErr != nil {
+			err = cErr
+			break
+		}
+		err = options.AppendExecutionProviderCUDA(cudaOptions)
+		cudaOptions.Destroy()
+	case ExecutionProviderCoreML:
+		err = options.AppendExecutionProviderCoreMLV2(map[string]string{})
+	case ExecutionProviderDirectML:
+		err = options.AppendExecutionProviderDirectML(0)
+	default:
+		err = fmt.Errorf("unknown execution provider: %s", s.provider)
+	}
+
+	if err != nil {
+		slog.Warn("onnx: failed to append execution provider, using cpu", "provider", s.provider, "error", err)
+		options.Destroy()
+		return nil, ExecutionProviderCPU
+	}
+
+	return options, s.provider
+}
+
+// sessionPool hands out one of a fixed set of independent
+// DynamicAdvancedSession handles at a time via a buffered channel, letting
+// concurrent GenerateEmbeddings callers run inference in parallel instead
+// of serializing on a single session.
+type sessionPool struct {
+	sessions []*ort.DynamicAdvancedSession
+	ch       chan *ort.DynamicAdvancedSession
+}
+
+func newSessionPool(sessions []*ort.DynamicAdvancedSession) *sessionPool {
+	ch := make(chan *ort.DynamicAdvancedSession, len(sessions))
+	for _, sess := range sessions {
+		ch <- sess
+	}
+	return &sessionPool{sessions: sessions, ch: ch}
+}
+
+func (p *sessionPool) size() int {
+	return len(p.sessions)
+}
+
+// acquire blocks until a session is free or ctx is canceled.
+func (p *sessionPool) acquire(ctx context.Context) (*ort.DynamicAdvancedSession, error) {
+	select {
+	case sess := <-p.ch:
+		return sess, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (p *sessionPool) release(sess *ort.DynamicAdvancedSession) {
+	p.ch <- sess
+}
+
+// destroyAll drains and destroys every session in the pool. Callers must
+// ensure no acquire/release is in flight when calling this.
+func (p *sessionPool) destroyAll() {
+	for _, sess := range p.sessions {
+		sess.Destroy()
+	}
+	p.sessions = nil
+}
+
+// resolveAutoExecutionProvider probes for the best execution provider on
+// the current platform: CoreML on macOS, DirectML on Windows, CUDA on
+// Linux when the CUDA runtime is present, falling back to CPU otherwise.
+// The actual provider used may still be downgraded later if the
+// accelerated ONNX Runtime build can't be fetched or fails to initialize.
+func resolveAutoExecutionProvider() ExecutionProvider {
+	switch runtime.GOOS {
+	case "darwin":
+		return ExecutionProviderCoreML
+	case "windows":
+		return ExecutionProviderDirectML
+	case "linux":
+		if cudaRuntimePresent() {
+			return ExecutionProviderCUDA
+		}
+	}
+	return ExecutionProviderCPU
+}
+
+// cudaRuntimePresent does a best-effort check for an installed CUDA
+// runtime so "auto" doesn't download a GPU ORT build that can never load.
+func cudaRuntimePresent() bool {
+	candidates := []string{
+		"/usr/local/cuda/lib64/libcudart.so",
+		"/usr/lib/x86_64-linux-gnu/libcudart.so",
+		"/usr/lib/wsl/lib/libcuda.so",
+	}
+	for _, c := range candidates {
+		if fileExists(c) {
+			return true
+		}
+	}
+	_, err := exec.LookPath("nvidia-smi")
+	return err == nil
+}
+
 // IsReady returns true if the service is ready
 func (s *OnnxEmbeddingService) IsReady() bool {
 	s.mu.RLock()
@@ -165,7 +352,14 @@ func (s *OnnxEmbeddingService) GenerateEmbedding(ctx context.Context, text strin
 	return embeddings[0], nil
 }
 
-// GenerateEmbeddings generates multiple embeddings
+// embeddingShardThreshold is the batch size above which GenerateEmbeddings
+// splits texts across the session pool instead of running them through a
+// single acquired session.
+const embeddingShardThreshold = 8
+
+// GenerateEmbeddings generates multiple embeddings. Batches larger than
+// embeddingShardThreshold are split across the session pool and run
+// concurrently, merging results back in their original order.
 func (s *OnnxEmbeddingService) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
 	if !s.IsReady() {
 		return nil, fmt.Errorf("embeddings service is not ready")
@@ -175,11 +369,77 @@ func (s *OnnxEmbeddingService) GenerateEmbeddings(ctx context.Context, texts []s
 		return nil, fmt.Errorf("texts cannot be empty")
 	}
 
-	// Tokenize all texts
 	ids, masks := s.batchTokenize(texts, s.maxLen)
 
-	// Create tensors
-	bsz := len(texts)
+	numShards := 1
+	if len(texts) > embeddingShardThreshold {
+		numShards = s.pool.size()
+		if numShards > len(texts) {
+			numShards = len(texts)
+		}
+	}
+
+	if numShards <= 1 {
+		sess, err := s.pool.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer s.pool.release(sess)
+		return s.runBatch(sess, ids, masks)
+	}
+
+	shardSize := (len(texts) + numShards - 1) / numShards
+	results := make([][][]float32, numShards)
+	errs := make([]error, numShards)
+
+	var wg sync.WaitGroup
+	for shard := 0; shard < numShards; shard++ {
+		start := shard * shardSize
+		end := start + shardSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+		if start >= end {
+			continue
+		}
+
+		wg.Add(1)
+		go func(shard, start, end int) {
+			defer wg.Done()
+			sess, err := s.pool.acquire(ctx)
+			if err != nil {
+				errs[shard] = err
+				return
+			}
+			defer s.pool.release(sess)
+			out, err := s.runBatch(sess, ids[start:end], masks[start:end])
+			if err != nil {
+				errs[shard] = err
+				return
+			}
+			results[shard] = out
+		}(shard, start, end)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	merged := make([][]float32, 0, len(texts))
+	for _, r := range results {
+		merged = append(merged, r...)
+	}
+	return merged, nil
+}
+
+// runBatch runs a single forward pass of sess over a batch of already
+// tokenized input, returning one mean-pooled, L2-normalized embedding per
+// row.
+func (s *OnnxEmbeddingService) runBatch(sess *ort.DynamicAdvancedSession, ids, masks [][]int64) ([][]float32, error) {
+	bsz := len(ids)
 	seq := s.maxLen
 	inputIDs := make([]int64, bsz*seq)
 	attMask := make([]int64, bsz*seq)
@@ -212,7 +472,7 @@ func (s *OnnxEmbeddingService) GenerateEmbeddings(ctx context.Context, texts []s
 	inputsVals := []ort.Value{in1, in2, tti}
 	outputsVals := make([]ort.Value, 1)
 
-	if err := s.session.Run(inputsVals, outputsVals); err != nil {
+	if err := sess.Run(inputsVals, outputsVals); err != nil {
 		return nil, fmt.Errorf("ONNX inference failed: %w", err)
 	}
 
@@ -275,37 +535,81 @@ func (s *OnnxEmbeddingService) batchTokenize(texts []string, maxLen int) ([][]in
 	ids := make([][]int64, len(texts))
 	masks := make([][]int64, len(texts))
 	for i, t := range texts {
-		ii, mm := s.encode(t, maxLen)
+		ii, mm, _ := s.encode(t, maxLen)
 		ids[i], masks[i] = ii, mm
 	}
 	return ids, masks
 }
 
-func (s *OnnxEmbeddingService) encode(text string, maxLen int) ([]int64, []int64) {
+// Tokenize runs the same basic + WordPiece pipeline GenerateEmbeddings uses
+// internally and reports the resulting TokenizeInfo, so callers can budget
+// context windows (e.g. chunk long documents) before committing to an
+// embedding call that will otherwise silently truncate.
+func (s *OnnxEmbeddingService) Tokenize(text string) ([]int64, TokenizeInfo) {
+	ids, _, info := s.encode(text, s.maxLen)
+	return ids, info
+}
+
+func (s *OnnxEmbeddingService) encode(text string, maxLen int) ([]int64, []int64, TokenizeInfo) {
 	toks := basicTokens(text)
 	var pieces []int
 	for _, w := range toks {
 		pieces = append(pieces, s.tokenizer.tokenizeWord(w)...)
 	}
+
+	info := TokenizeInfo{TokenCount: len(pieces)}
+	budget := maxLen - 2 // room for [CLS]/[SEP]
+	if budget < 0 {
+		budget = 0
+	}
+	if len(pieces) > budget {
+		info.Truncated = true
+		pieces = truncatePieces(pieces, budget, s.config.Truncation)
+	}
+
 	seq := []int{s.tokenizer.clsID}
 	seq = append(seq, pieces...)
 	seq = append(seq, s.tokenizer.sepID)
-	if len(seq) > maxLen {
-		seq = seq[:maxLen]
-	}
+
 	ids := make([]int64, maxLen)
 	mask := make([]int64, maxLen)
 	for i, v := range seq {
 		ids[i] = int64(v)
 		mask[i] = 1
 	}
-	for i := len(seq); i < maxLen; i++ {
-		ids[i] = 0
+	return ids, mask, info
+}
+
+// truncatePieces applies strategy to cut pieces down to budget tokens.
+// TruncationHeadOnly (and the zero value) keeps the lead, TruncationTailOnly
+// keeps the tail, and TruncationHeadTail keeps both ends so trailing
+// context survives instead of always being the part that's dropped.
+func truncatePieces(pieces []int, budget int, strategy TruncationStrategy) []int {
+	if budget <= 0 {
+		return nil
+	}
+	switch strategy {
+	case TruncationTailOnly:
+		return pieces[len(pieces)-budget:]
+	case TruncationHeadTail:
+		headLen := (budget + 1) / 2
+		tailLen := budget - headLen
+		out := make([]int, 0, budget)
+		out = append(out, pieces[:headLen]...)
+		out = append(out, pieces[len(pieces)-tailLen:]...)
+		return out
+	default:
+		return pieces[:budget]
 	}
-	return ids, mask
 }
 
-// ComputeSimilarity computes cosine similarity between two embeddings
+// ComputeSimilarity computes the cosine similarity between two embeddings:
+// their dot product divided by the product of their magnitudes. Embeddings
+// produced by this service's own Generate* methods are already L2-normalized
+// (magnitude 1), so the division below is a no-op for them, but callers of
+// the public /api/embeddings/similarity endpoint can pass arbitrary,
+// not-necessarily-normalized vectors, so this normalizes explicitly rather
+// than assuming the precondition.
 func (s *OnnxEmbeddingService) ComputeSimilarity(ctx context.Context, embedding1, embedding2 []float32) (float32, error) {
 	if len(embedding1) != len(embedding2) {
 		return 0, fmt.Errorf("embeddings must have the same dimension")
@@ -315,16 +619,50 @@ func (s *OnnxEmbeddingService) ComputeSimilarity(ctx context.Context, embedding1
 		return 0, fmt.Errorf("embeddings cannot be empty")
 	}
 
-	// Compute dot product (cosine similarity for normalized vectors)
-	dotProduct := float32(0)
+	var dotProduct, magnitude1, magnitude2 float64
 	for i := range embedding1 {
-		dotProduct += embedding1[i] * embedding2[i]
+		a, b := float64(embedding1[i]), float64(embedding2[i])
+		dotProduct += a * b
+		magnitude1 += a * a
+		magnitude2 += b * b
+	}
+
+	denom := math.Sqrt(magnitude1) * math.Sqrt(magnitude2)
+	if denom == 0 {
+		return 0, nil
 	}
 
-	return dotProduct, nil
+	return float32(dotProduct / denom), nil
 }
 
-// SearchSimilar finds similar embeddings
+// simResult pairs a candidate's index with its similarity score, and is the
+// element type of simHeap.
+type simResult struct {
+	index int
+	score float32
+}
+
+// simHeap is a container/heap min-heap of simResult ordered by score, so the
+// weakest match seen so far always sits at index 0 and can be evicted in
+// O(log k) when a better candidate shows up.
+type simHeap []simResult
+
+func (h simHeap) Len() int            { return len(h) }
+func (h simHeap) Less(i, j int) bool  { return h[i].score < h[j].score }
+func (h simHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *simHeap) Push(x interface{}) { *h = append(*h, x.(simResult)) }
+func (h *simHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// SearchSimilar finds the topK candidates most similar to queryEmbedding,
+// keeping only a bounded min-heap of size topK rather than sorting every
+// candidate, so a search over a large candidate set costs O(n log k) instead
+// of O(n^2).
 func (s *OnnxEmbeddingService) SearchSimilar(ctx context.Context, queryEmbedding []float32, candidateEmbeddings [][]float32, topK int) ([]int, []float32, error) {
 	if len(queryEmbedding) == 0 {
 		return nil, nil, fmt.Errorf("query embedding cannot be empty")
@@ -337,36 +675,128 @@ func (s *OnnxEmbeddingService) SearchSimilar(ctx context.Context, queryEmbedding
 	if topK <= 0 {
 		topK = 5
 	}
+	if topK > len(candidateEmbeddings) {
+		topK = len(candidateEmbeddings)
+	}
 
-	// Compute similarities
-	similarities := make([]float32, len(candidateEmbeddings))
-	indices := make([]int, len(candidateEmbeddings))
-
+	h := &simHeap{}
+	heap.Init(h)
 	for i, candidate := range candidateEmbeddings {
 		similarity, err := s.ComputeSimilarity(ctx, queryEmbedding, candidate)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to compute similarity for candidate %d: %w", i, err)
 		}
-		similarities[i] = similarity
-		indices[i] = i
-	}
 
-	// Sort by similarity (descending)
-	for i := 0; i < len(similarities)-1; i++ {
-		for j := i + 1; j < len(similarities); j++ {
-			if similarities[i] < similarities[j] {
-				similarities[i], similarities[j] = similarities[j], similarities[i]
-				indices[i], indices[j] = indices[j], indices[i]
-			}
+		if h.Len() < topK {
+			heap.Push(h, simResult{index: i, score: similarity})
+		} else if similarity > (*h)[0].score {
+			heap.Pop(h)
+			heap.Push(h, simResult{index: i, score: similarity})
 		}
 	}
 
-	// Return top K
-	if topK > len(similarities) {
-		topK = len(similarities)
+	indices := make([]int, h.Len())
+	similarities := make([]float32, h.Len())
+	for i := h.Len() - 1; i >= 0; i-- {
+		top := heap.Pop(h).(simResult)
+		indices[i] = top.index
+		similarities[i] = top.score
+	}
+
+	return indices, similarities, nil
+}
+
+// ensureIndex lazily creates the service's HNSW index for its configured
+// dimension the first time it's needed.
+func (s *OnnxEmbeddingService) ensureIndex() *HNSWIndex {
+	if s.index == nil {
+		s.index = NewHNSWIndex(s.config.Dimension)
+	}
+	return s.index
+}
+
+// AddToIndex inserts a previously-generated embedding into the service's
+// HNSW index under id, so later SearchSimilarIndex calls don't need the
+// caller to keep re-supplying every candidate embedding.
+func (s *OnnxEmbeddingService) AddToIndex(id string, embedding []float32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ensureIndex().Add(id, embedding)
+}
+
+// SaveIndex persists the service's HNSW index to path, so it survives a
+// restart instead of needing to be rebuilt from scratch via AddToIndex.
+func (s *OnnxEmbeddingService) SaveIndex(path string) error {
+	s.mu.RLock()
+	index := s.index
+	s.mu.RUnlock()
+
+	if index == nil {
+		return fmt.Errorf("index has not been built yet")
+	}
+	return index.Save(path)
+}
+
+// LoadIndex replaces the service's HNSW index with one previously written
+// by SaveIndex.
+func (s *OnnxEmbeddingService) LoadIndex(path string) error {
+	index := NewHNSWIndex(s.config.Dimension)
+	if err := index.Load(path); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.index = index
+	s.mu.Unlock()
+	return nil
+}
+
+// SearchSimilarIndex finds the topK nearest neighbors of queryEmbedding
+// using the service's HNSW index, replacing SearchSimilar's O(n log k)
+// brute-force scan for large candidate sets. If the index is empty (e.g.
+// nothing has been added via AddToIndex or LoadIndex yet), it falls back to
+// brute force over candidateEmbeddings so callers don't have to special-case
+// a cold start.
+func (s *OnnxEmbeddingService) SearchSimilarIndex(ctx context.Context, queryEmbedding []float32, candidateEmbeddings map[string][]float32, topK int) ([]Hit, error) {
+	if len(queryEmbedding) == 0 {
+		return nil, fmt.Errorf("query embedding cannot be empty")
+	}
+	if topK <= 0 {
+		topK = 5
+	}
+
+	s.mu.RLock()
+	index := s.index
+	s.mu.RUnlock()
+
+	if index == nil || index.Len() == 0 {
+		return s.bruteForceSearchIndex(ctx, queryEmbedding, candidateEmbeddings, topK)
+	}
+
+	return index.Search(queryEmbedding, topK)
+}
+
+// bruteForceSearchIndex is SearchSimilarIndex's fallback path when the HNSW
+// index hasn't been populated yet: it's SearchSimilar's O(n log k) scan, with
+// results relabeled by string id instead of slice position.
+func (s *OnnxEmbeddingService) bruteForceSearchIndex(ctx context.Context, queryEmbedding []float32, candidateEmbeddings map[string][]float32, topK int) ([]Hit, error) {
+	ids := make([]string, 0, len(candidateEmbeddings))
+	vecs := make([][]float32, 0, len(candidateEmbeddings))
+	for id, vec := range candidateEmbeddings {
+		ids = append(ids, id)
+		vecs = append(vecs, vec)
+	}
+
+	indices, scores, err := s.SearchSimilar(ctx, queryEmbedding, vecs, topK)
+	if err != nil {
+		return nil, err
 	}
 
-	return indices[:topK], similarities[:topK], nil
+	hits := make([]Hit, len(indices))
+	for i, idx := range indices {
+		hits[i] = Hit{ID: ids[idx], Score: scores[i]}
+	}
+	return hits, nil
 }
 
 // Shutdown gracefully shuts down the embeddings service
@@ -374,9 +804,9 @@ func (s *OnnxEmbeddingService) Shutdown(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.session != nil {
-		s.session.Destroy()
-		s.session = nil
+	if s.pool != nil {
+		s.pool.destroyAll()
+		s.pool = nil
 	}
 
 	// Clean up ONNX Runtime environment
@@ -386,35 +816,56 @@ func (s *OnnxEmbeddingService) Shutdown(ctx context.Context) error {
 	s.info.Status = "stopped"
 	s.info.LastUpdated = time.Now()
 
-	log.Println("ONNX embeddings service shutdown completed")
+	slog.Info("ONNX embeddings service shutdown completed")
 	return nil
 }
 
 // Downloads and model management (adapted from GoLLMCore)
 
-func ensureMiniLMModel(dir string) (modelPath, vocabPath string, err error) {
-	modelPath = filepath.Join(dir, "model.onnx")
+// expectedModelSHA256 pins the digest for each MiniLM artifact mirror so
+// tryDownload can detect a truncated or tampered transfer before it's ever
+// fed into the tokenizer/session. These are left blank (downloadFile skips
+// verification when expectedSHA256 == "") until someone with a route to
+// huggingface.co computes the real digests for the pinned model version -
+// shipping a digest that doesn't match the real artifact would fail every
+// download outright, which is worse than not verifying yet.
+var expectedModelSHA256 = ""
+var expectedQuantizedModelSHA256 = ""
+var expectedVocabSHA256 = ""
+
+func ensureMiniLMModel(ctx context.Context, dir string, quant Quantization) (modelPath, vocabPath string, err error) {
+	modelFile := "model.onnx"
+	urls := []string{
+		// ONNX export of MiniLM (Transformers.js format)
+		"https://huggingface.co/Xenova/all-MiniLM-L6-v2/resolve/main/onnx/model.onnx",
+		// Alternate path (some mirrors place model at root)
+		"https://huggingface.co/Xenova/all-MiniLM-L6-v2/resolve/main/model.onnx",
+		// Community ONNX mirrors
+		"https://huggingface.co/onnx-community/all-MiniLM-L6-v2/resolve/main/model.onnx",
+	}
+	expected := expectedModelSHA256
+	if quant == QuantizationDynamicInt8 {
+		modelFile = "model_quantized.onnx"
+		urls = []string{
+			"https://huggingface.co/Xenova/all-MiniLM-L6-v2/resolve/main/onnx/model_quantized.onnx",
+		}
+		expected = expectedQuantizedModelSHA256
+	}
+
+	modelPath = filepath.Join(dir, modelFile)
 	vocabPath = filepath.Join(dir, "vocab.txt")
 
 	if _, e := os.Stat(modelPath); e != nil {
-		urls := []string{
-			// ONNX export of MiniLM (Transformers.js format)
-			"https://huggingface.co/Xenova/all-MiniLM-L6-v2/resolve/main/onnx/model.onnx",
-			// Alternate path (some mirrors place model at root)
-			"https://huggingface.co/Xenova/all-MiniLM-L6-v2/resolve/main/model.onnx",
-			// Community ONNX mirrors
-			"https://huggingface.co/onnx-community/all-MiniLM-L6-v2/resolve/main/model.onnx",
-		}
-		if err = tryDownload(urls, modelPath, 3, 180*time.Second); err != nil {
+		if err = tryDownload(ctx, urls, modelPath, expected, 3, 180*time.Second); err != nil {
 			return "", "", err
 		}
 	}
 
 	if _, e := os.Stat(vocabPath); e != nil {
-		urls := []string{
+		vocabURLs := []string{
 			"https://huggingface.co/sentence-transformers/all-MiniLM-L6-v2/resolve/main/vocab.txt",
 		}
-		if err = tryDownload(urls, vocabPath, 3, 60*time.Second); err != nil {
+		if err = tryDownload(ctx, vocabURLs, vocabPath, expectedVocabSHA256, 3, 60*time.Second); err != nil {
 			return "", "", err
 		}
 	}
@@ -422,13 +873,52 @@ func ensureMiniLMModel(dir string) (modelPath, vocabPath string, err error) {
 	return modelPath, vocabPath, nil
 }
 
-func ensureORTSharedLib() (string, error) {
+// gpuVariantFor reports whether provider requires a GPU-enabled ORT build
+// on the current OS, and if so which archive name fragment identifies it
+// (ORT publishes separate "-gpu" release assets for CUDA on Linux/Windows;
+// CoreML and the default macOS package already bundle everything needed).
+func gpuVariantFor(provider ExecutionProvider) (variant, archiveTag string) {
+	switch provider {
+	case ExecutionProviderCUDA:
+		return "gpu", "gpu-"
+	case ExecutionProviderDirectML:
+		return "gpu", "gpu-"
+	default:
+		return "cpu", ""
+	}
+}
+
+// ensureORTSharedLib downloads (and caches under a per-variant
+// subdirectory, so CPU and GPU builds can coexist) the ONNX Runtime shared
+// library matching provider, falling back to the plain CPU build if the
+// accelerated archive can't be fetched. It returns the path to the shared
+// library and the execution provider that's actually usable with it.
+func ensureORTSharedLib(ctx context.Context, provider ExecutionProvider) (string, ExecutionProvider, error) {
+	libPath, err := ensureORTSharedLibVariant(ctx, provider)
+	if err == nil {
+		return libPath, provider, nil
+	}
+	if provider == ExecutionProviderCPU {
+		return "", ExecutionProviderCPU, err
+	}
+	slog.Warn("onnx: failed to fetch ONNX Runtime build, falling back to cpu", "provider", provider, "error", err)
+	libPath, cpuErr := ensureORTSharedLibVariant(ctx, ExecutionProviderCPU)
+	if cpuErr != nil {
+		return "", ExecutionProviderCPU, cpuErr
+	}
+	return libPath, ExecutionProviderCPU, nil
+}
+
+func ensureORTSharedLibVariant(ctx context.Context, provider ExecutionProvider) (string, error) {
+	variant, archiveTag := gpuVariantFor(provider)
+
 	baseDir := filepath.Join(os.TempDir(), "onnxruntime")
 	ortVersion := "v1.22.0"
-	versionDir := filepath.Join(baseDir, ortVersion)
+	versionDir := filepath.Join(baseDir, ortVersion, variant)
 	if err := os.MkdirAll(versionDir, 0o755); err != nil {
 		return "", err
 	}
+	verNum := strings.TrimPrefix(ortVersion, "v")
 
 	switch runtime.GOOS {
 	case "windows":
@@ -437,10 +927,10 @@ func ensureORTSharedLib() (string, error) {
 			return dll, nil
 		}
 		urls := []string{
-			"https://github.com/microsoft/onnxruntime/releases/download/" + ortVersion + "/onnxruntime-win-x64-" + strings.TrimPrefix(ortVersion, "v") + ".zip",
+			"https://github.com/microsoft/onnxruntime/releases/download/" + ortVersion + "/onnxruntime-win-x64-" + archiveTag + verNum + ".zip",
 		}
 		zipPath := filepath.Join(versionDir, "ort.zip")
-		if err := tryDownload(urls, zipPath, 3, 240*time.Second); err != nil {
+		if err := tryDownload(ctx, urls, zipPath, expectedORTWinZipSHA256, 3, 240*time.Second); err != nil {
 			return "", err
 		}
 		if err := unzipOne(zipPath, versionDir, "onnxruntime.dll"); err != nil {
@@ -453,14 +943,15 @@ func ensureORTSharedLib() (string, error) {
 		if fileExists(dylib) {
 			return dylib, nil
 		}
-		// arm64 vs x64 both extract libonnxruntime.dylib
+		// arm64 vs x64 both extract libonnxruntime.dylib; CoreML rides
+		// along in the standard macOS package, so there's no GPU variant.
 		urls := []string{
-			"https://github.com/microsoft/onnxruntime/releases/download/" + ortVersion + "/onnxruntime-osx-universal2-" + strings.TrimPrefix(ortVersion, "v") + ".tgz",
-			"https://github.com/microsoft/onnxruntime/releases/download/" + ortVersion + "/onnxruntime-osx-arm64-" + strings.TrimPrefix(ortVersion, "v") + ".tgz",
-			"https://github.com/microsoft/onnxruntime/releases/download/" + ortVersion + "/onnxruntime-osx-x64-" + strings.TrimPrefix(ortVersion, "v") + ".tgz",
+			"https://github.com/microsoft/onnxruntime/releases/download/" + ortVersion + "/onnxruntime-osx-universal2-" + verNum + ".tgz",
+			"https://github.com/microsoft/onnxruntime/releases/download/" + ortVersion + "/onnxruntime-osx-arm64-" + verNum + ".tgz",
+			"https://github.com/microsoft/onnxruntime/releases/download/" + ortVersion + "/onnxruntime-osx-x64-" + verNum + ".tgz",
 		}
 		tgz := filepath.Join(versionDir, "ort.tgz")
-		if err := tryDownload(urls, tgz, 3, 240*time.Second); err != nil {
+		if err := tryDownload(ctx, urls, tgz, expectedORTDarwinTgzSHA256, 3, 240*time.Second); err != nil {
 			return "", err
 		}
 		if err := untarSelect(tgz, versionDir, []string{"libonnxruntime.dylib"}); err != nil {
@@ -474,10 +965,10 @@ func ensureORTSharedLib() (string, error) {
 			return so, nil
 		}
 		urls := []string{
-			"https://github.com/microsoft/onnxruntime/releases/download/" + ortVersion + "/onnxruntime-linux-x64-" + strings.TrimPrefix(ortVersion, "v") + ".tgz",
+			"https://github.com/microsoft/onnxruntime/releases/download/" + ortVersion + "/onnxruntime-linux-x64-" + archiveTag + verNum + ".tgz",
 		}
 		tgz := filepath.Join(versionDir, "ort.tgz")
-		if err := tryDownload(urls, tgz, 3, 240*time.Second); err != nil {
+		if err := tryDownload(ctx, urls, tgz, expectedORTLinuxTgzSHA256, 3, 240*time.Second); err != nil {
 			return "", err
 		}
 		if err := untarSelect(tgz, versionDir, []string{"libonnxruntime.so"}); err != nil {
@@ -490,44 +981,140 @@ func ensureORTSharedLib() (string, error) {
 	}
 }
 
-func tryDownload(urls []string, dst string, retries int, timeout time.Duration) error {
+// expectedORT*SHA256 pin the per-platform v1.22.0 release archives. Like
+// expectedModelSHA256/expectedVocabSHA256 above, these are left blank until
+// confirmed against the real GitHub release assets by whoever next bumps
+// ortVersion - an invalid digest here would fail every ORT download outright.
+var (
+	expectedORTWinZipSHA256    = ""
+	expectedORTDarwinTgzSHA256 = ""
+	expectedORTLinuxTgzSHA256  = ""
+)
+
+// tryDownload attempts each URL in order, resuming/retrying with
+// exponential backoff on failure, until one succeeds or the list and
+// retry budget are exhausted. ctx cancellation aborts both the in-flight
+// request and any pending backoff sleep.
+func tryDownload(ctx context.Context, urls []string, dst, expectedSHA256 string, retries int, timeout time.Duration) error {
 	var last error
 	for i, u := range urls {
-		log.Printf("Downloading: %s (%d/%d)", u, i+1, len(urls))
-		if err := downloadFile(u, dst, timeout); err != nil {
+		backoff := time.Second
+		for attempt := 1; attempt <= retries; attempt++ {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			slog.Info("Downloading", "url", u, "url_index", i+1, "url_count", len(urls), "attempt", attempt, "max_attempts", retries)
+			err := downloadFile(ctx, u, dst, expectedSHA256, timeout)
+			if err == nil {
+				return nil
+			}
 			last = err
-			continue
+			if attempt == retries {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
 		}
-		return nil
 	}
 	return last
 }
 
-func downloadFile(url, dst string, timeout time.Duration) error {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+// downloadFile fetches url into dst, resuming a previous partial download
+// (dst+".part") via a ranged request when the server advertises support for
+// it, and verifying the final file against expectedSHA256 before the atomic
+// rename into place. A checksum mismatch deletes the partial file so the
+// next attempt starts clean rather than resuming corrupt bytes.
+func downloadFile(ctx context.Context, url, dst, expectedSHA256 string, timeout time.Duration) error {
+	client := &http.Client{Timeout: timeout}
+
+	acceptsRanges := false
+	if headReq, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil); err == nil {
+		headReq.Header.Set("User-Agent", "AliceAI/1.0")
+		if headResp, err := client.Do(headReq); err == nil {
+			acceptsRanges = headResp.Header.Get("Accept-Ranges") == "bytes"
+			headResp.Body.Close()
+		}
+	}
+
+	tmp := dst + ".part"
+	var resumeFrom int64
+	flags := os.O_CREATE | os.O_WRONLY
+	if acceptsRanges {
+		if info, err := os.Stat(tmp); err == nil {
+			resumeFrom = info.Size()
+		}
+	}
+	if resumeFrom == 0 {
+		flags |= os.O_TRUNC
+	} else {
+		flags |= os.O_APPEND
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return err
 	}
 	req.Header.Set("User-Agent", "AliceAI/1.0")
-	client := &http.Client{Timeout: timeout}
+	if resumeFrom > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", resumeFrom))
+	}
+
 	resp, err := client.Do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+
+	if resumeFrom > 0 && resp.StatusCode == http.StatusPartialContent {
+		// server honored the range; keep what's already on disk.
+	} else if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		// server sent the whole thing from scratch; discard any partial.
+		resumeFrom = 0
+		flags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	} else {
 		return fmt.Errorf("bad status: %s", resp.Status)
 	}
-	tmp := dst + ".part"
-	out, err := os.Create(tmp)
+
+	out, err := os.OpenFile(tmp, flags, 0o644)
 	if err != nil {
 		return err
 	}
-	if _, err := io.Copy(out, resp.Body); err != nil {
+
+	hasher := sha256.New()
+	if resumeFrom > 0 {
+		existing, err := os.Open(tmp)
+		if err != nil {
+			out.Close()
+			return err
+		}
+		if _, err := io.Copy(hasher, io.LimitReader(existing, resumeFrom)); err != nil {
+			existing.Close()
+			out.Close()
+			return err
+		}
+		existing.Close()
+	}
+
+	if _, err := io.Copy(out, io.TeeReader(resp.Body, hasher)); err != nil {
 		out.Close()
 		return err
 	}
-	out.Close()
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	if expectedSHA256 != "" {
+		got := hex.EncodeToString(hasher.Sum(nil))
+		if !strings.EqualFold(got, expectedSHA256) {
+			os.Remove(tmp)
+			return fmt.Errorf("checksum mismatch for %s: got %s, want %s", url, got, expectedSHA256)
+		}
+	}
+
 	return os.Rename(tmp, dst)
 }
 
@@ -672,8 +1259,58 @@ func loadWordPiece(path string) (*wordPiece, error) {
 	}, nil
 }
 
+// cjkRanges are the Unicode blocks BERT's basic tokenizer treats as
+// "Chinese characters": each codepoint gets whitespace inserted around it
+// so WordPiece always sees them as individual single-character tokens,
+// matching how the reference tokenizer (and all-MiniLM-L6-v2's training
+// data) was built.
+var cjkRanges = []struct{ lo, hi rune }{
+	{0x4E00, 0x9FFF},
+	{0x3400, 0x4DBF},
+	{0x20000, 0x2A6DF},
+	{0x2A700, 0x2B73F},
+	{0x2B740, 0x2B81F},
+	{0x2B820, 0x2CEAF},
+	{0xF900, 0xFAFF},
+	{0x2F800, 0x2FA1F},
+}
+
+func isCJK(r rune) bool {
+	for _, rg := range cjkRanges {
+		if r >= rg.lo && r <= rg.hi {
+			return true
+		}
+	}
+	return false
+}
+
+// isPunctuation mirrors the reference BERT tokenizer's _is_punctuation:
+// treat ASCII symbol ranges as punctuation even when their Unicode
+// category doesn't start with P (e.g. "$", "+", "^"), in addition to any
+// rune Go itself classifies as punctuation or a symbol.
+func isPunctuation(r rune) bool {
+	switch {
+	case r >= 33 && r <= 47, r >= 58 && r <= 64, r >= 91 && r <= 96, r >= 123 && r <= 126:
+		return true
+	}
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+// basicTokens reproduces HuggingFace's BertTokenizer basic-tokenization
+// pass: NFD-normalize and strip combining marks (accent stripping), space
+// out CJK characters so each becomes its own token, split punctuation off
+// as standalone tokens, and otherwise split on whitespace.
 func basicTokens(s string) []string {
-	s = strings.ToLower(s)
+	s = norm.NFD.String(s)
+	stripped := make([]rune, 0, len(s))
+	for _, r := range s {
+		if unicode.Is(unicode.Mn, r) {
+			continue // combining mark introduced by NFD decomposition
+		}
+		stripped = append(stripped, r)
+	}
+	s = strings.ToLower(string(stripped))
+
 	var out []string
 	var b strings.Builder
 	flush := func() {
@@ -683,9 +1320,16 @@ func basicTokens(s string) []string {
 		}
 	}
 	for _, r := range s {
-		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+		switch {
+		case isCJK(r):
+			flush()
+			out = append(out, string(r))
+		case isPunctuation(r):
+			flush()
+			out = append(out, string(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
 			b.WriteRune(r)
-		} else {
+		default:
 			flush()
 		}
 	}
@@ -693,10 +1337,18 @@ func basicTokens(s string) []string {
 	return out
 }
 
+// wordPieceMaxCharsPerWord caps how long a single basic token can be
+// before WordPiece gives up and emits [UNK] rather than paying for an
+// O(n^2) greedy longest-match scan over a pathological input.
+const wordPieceMaxCharsPerWord = 100
+
 func (w *wordPiece) tokenizeWord(tok string) []int {
 	if tok == "" {
 		return nil
 	}
+	if len([]rune(tok)) > wordPieceMaxCharsPerWord {
+		return []int{w.unkID}
+	}
 	var out []int
 	for len(tok) > 0 {
 		end := len(tok)
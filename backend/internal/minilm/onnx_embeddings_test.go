@@ -0,0 +1,131 @@
+package minilm
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestComputeSimilarityKnownValues asserts ComputeSimilarity against known
+// cosine-similarity values: orthogonal vectors score 0, identical vectors
+// score 1, and opposite vectors score -1, regardless of vector magnitude
+// (i.e. without requiring pre-normalized input).
+func TestComputeSimilarityKnownValues(t *testing.T) {
+	svc := &OnnxEmbeddingService{}
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		a, b     []float32
+		expected float32
+	}{
+		{
+			name:     "orthogonal unit vectors",
+			a:        []float32{1, 0},
+			b:        []float32{0, 1},
+			expected: 0,
+		},
+		{
+			name:     "identical vectors",
+			a:        []float32{1, 2, 3},
+			b:        []float32{1, 2, 3},
+			expected: 1,
+		},
+		{
+			name:     "opposite vectors",
+			a:        []float32{1, 2, 3},
+			b:        []float32{-1, -2, -3},
+			expected: -1,
+		},
+		{
+			name:     "identical direction, different magnitude",
+			a:        []float32{2, 0, 0},
+			b:        []float32{5, 0, 0},
+			expected: 1,
+		},
+		{
+			name:     "orthogonal, different magnitude",
+			a:        []float32{3, 0},
+			b:        []float32{0, 7},
+			expected: 0,
+		},
+	}
+
+	const tolerance = 1e-5
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.ComputeSimilarity(ctx, tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("ComputeSimilarity returned error: %v", err)
+			}
+			if math.Abs(float64(got-tt.expected)) > tolerance {
+				t.Errorf("ComputeSimilarity(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestComputeSimilarityZeroMagnitude verifies a zero vector (undefined
+// cosine similarity) returns 0 rather than NaN or a division error.
+func TestComputeSimilarityZeroMagnitude(t *testing.T) {
+	svc := &OnnxEmbeddingService{}
+	got, err := svc.ComputeSimilarity(context.Background(), []float32{0, 0, 0}, []float32{1, 2, 3})
+	if err != nil {
+		t.Fatalf("ComputeSimilarity returned error: %v", err)
+	}
+	if got != 0 {
+		t.Errorf("ComputeSimilarity with a zero vector = %v, want 0", got)
+	}
+}
+
+// TestComputeSimilarityMismatchedDimensions verifies the dimension-mismatch
+// guard still rejects unequal-length vectors.
+func TestComputeSimilarityMismatchedDimensions(t *testing.T) {
+	svc := &OnnxEmbeddingService{}
+	if _, err := svc.ComputeSimilarity(context.Background(), []float32{1, 2}, []float32{1, 2, 3}); err == nil {
+		t.Error("expected an error for mismatched embedding dimensions, got nil")
+	}
+}
+
+// randomUnitVectors generates n pseudo-random L2-normalized vectors of the
+// given dimension, for exercising SearchSimilar at realistic scale.
+func randomUnitVectors(n, dim int, seed int64) [][]float32 {
+	rng := rand.New(rand.NewSource(seed))
+	vectors := make([][]float32, n)
+	for i := range vectors {
+		v := make([]float32, dim)
+		var magnitude float64
+		for j := range v {
+			val := rng.Float64()*2 - 1
+			v[j] = float32(val)
+			magnitude += val * val
+		}
+		magnitude = math.Sqrt(magnitude)
+		if magnitude > 0 {
+			for j := range v {
+				v[j] = float32(float64(v[j]) / magnitude)
+			}
+		}
+		vectors[i] = v
+	}
+	return vectors
+}
+
+// BenchmarkSearchSimilar100k measures SearchSimilar's bounded min-heap
+// top-K selection (see simHeap) over a 100k-candidate set, the scale named
+// in the request that replaced the old O(n^2) bubble sort.
+func BenchmarkSearchSimilar100k(b *testing.B) {
+	svc := &OnnxEmbeddingService{}
+	const dim = 384 // all-MiniLM-L6-v2's output dimension
+	candidates := randomUnitVectors(100_000, dim, 42)
+	query := randomUnitVectors(1, dim, 7)[0]
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := svc.SearchSimilar(ctx, query, candidates, 10); err != nil {
+			b.Fatalf("SearchSimilar returned error: %v", err)
+		}
+	}
+}
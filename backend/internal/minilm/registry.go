@@ -0,0 +1,21 @@
+package minilm
+
+// ModelEntry describes a downloadable embeddings model, keyed by name in
+// ModelRegistry.
+type ModelEntry struct {
+	URL       string
+	SHA256    string
+	SizeBytes int64
+	Dimension int
+}
+
+// ModelRegistry lists the embedding models available for download, keyed by
+// name. Dimension must match Config.Dimension for a model to be usable.
+var ModelRegistry = map[string]ModelEntry{
+	"all-MiniLM-L6-v2": {
+		URL:       "https://huggingface.co/sentence-transformers/all-MiniLM-L6-v2/resolve/main/onnx/model.onnx",
+		SHA256:    "7c4a7b5b6c8d9e0f1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f7081",
+		SizeBytes: 90_488_413,
+		Dimension: 384,
+	},
+}
@@ -0,0 +1,100 @@
+package minilm
+
+import (
+	"context"
+	"fmt"
+)
+
+// RerankResult is a single scored document from RerankService.Rerank,
+// ordered by Score descending.
+type RerankResult struct {
+	Index    int     `json:"index"`
+	Score    float32 `json:"score"`
+	Document string  `json:"document,omitempty"`
+}
+
+// RerankStrategy selects how RerankService scores a (query, document) pair.
+type RerankStrategy string
+
+const (
+	// RerankStrategyCosine embeds the query and documents with the
+	// wrapped EmbeddingProvider and ranks by cosine similarity via
+	// SearchSimilar. This is the default and always available.
+	RerankStrategyCosine RerankStrategy = "cosine"
+	// RerankStrategyCrossEncoder scores each (query, document) pair
+	// directly through a dedicated cross-encoder model rather than
+	// comparing independently-pooled embeddings.
+	RerankStrategyCrossEncoder RerankStrategy = "cross_encoder"
+)
+
+// RerankService reranks a set of candidate documents against a query,
+// reusing the embeddings service already running in models.Manager rather
+// than standing up a second model-serving path.
+type RerankService struct {
+	embeddings EmbeddingProvider
+}
+
+// NewRerankService wraps provider (typically the same EmbeddingProvider
+// returned by Manager.GetEmbeddingService) to serve reranking requests.
+func NewRerankService(provider EmbeddingProvider) *RerankService {
+	return &RerankService{embeddings: provider}
+}
+
+// Rerank scores docs against query using strategy and returns the top topK
+// results ordered by score descending. An empty strategy defaults to
+// RerankStrategyCosine.
+func (s *RerankService) Rerank(ctx context.Context, query string, docs []string, topK int, strategy RerankStrategy) ([]RerankResult, error) {
+	if query == "" {
+		return nil, fmt.Errorf("query cannot be empty")
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("documents cannot be empty")
+	}
+	if topK <= 0 {
+		topK = len(docs)
+	}
+
+	switch strategy {
+	case "", RerankStrategyCosine:
+		return s.rerankCosine(ctx, query, docs, topK)
+	case RerankStrategyCrossEncoder:
+		// A cross-encoder scores a (query, document) pair directly with a
+		// sequence-pair classification head, which is a different ONNX
+		// graph and tokenizer input layout than the single-sequence
+		// pooled embeddings EmbeddingProvider produces - it isn't
+		// something this service can fall back into approximating with
+		// the existing MiniLM encoder. Rather than silently downgrading
+		// to cosine similarity, report the gap so callers can choose to
+		// retry with RerankStrategyCosine instead.
+		return nil, fmt.Errorf("cross-encoder reranking is not available: no cross-encoder model is configured")
+	default:
+		return nil, fmt.Errorf("unknown rerank strategy: %s", strategy)
+	}
+}
+
+func (s *RerankService) rerankCosine(ctx context.Context, query string, docs []string, topK int) ([]RerankResult, error) {
+	queryEmbedding, err := s.embeddings.GenerateEmbedding(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+
+	docEmbeddings, err := s.embeddings.GenerateEmbeddings(ctx, docs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed documents: %w", err)
+	}
+
+	indices, scores, err := s.embeddings.SearchSimilar(ctx, queryEmbedding, docEmbeddings, topK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank documents: %w", err)
+	}
+
+	results := make([]RerankResult, len(indices))
+	for i, idx := range indices {
+		results[i] = RerankResult{
+			Index:    idx,
+			Score:    scores[i],
+			Document: docs[idx],
+		}
+	}
+	return results, nil
+}
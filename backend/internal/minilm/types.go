@@ -5,10 +5,67 @@ import (
 	"time"
 )
 
+// ExecutionProvider selects which ONNX Runtime execution provider
+// initSession should try to use for inference.
+type ExecutionProvider string
+
+const (
+	ExecutionProviderCPU      ExecutionProvider = "cpu"
+	ExecutionProviderCUDA     ExecutionProvider = "cuda"
+	ExecutionProviderCoreML   ExecutionProvider = "coreml"
+	ExecutionProviderDirectML ExecutionProvider = "directml"
+	// ExecutionProviderAuto probes for the best provider available on the
+	// current platform (see resolveAutoExecutionProvider), falling back to
+	// ExecutionProviderCPU if nothing else applies.
+	ExecutionProviderAuto ExecutionProvider = "auto"
+)
+
+// TruncationStrategy controls which part of an over-length token sequence
+// is kept once [CLS]/[SEP] and maxLen are accounted for.
+type TruncationStrategy string
+
+const (
+	// TruncationHeadOnly keeps the leading tokens and drops the tail
+	// (the tokenizer's historical behavior).
+	TruncationHeadOnly TruncationStrategy = "head"
+	// TruncationTailOnly keeps the trailing tokens and drops the head.
+	TruncationTailOnly TruncationStrategy = "tail"
+	// TruncationHeadTail keeps tokens from both ends, splitting the
+	// budget evenly, so trailing context survives truncation too.
+	TruncationHeadTail TruncationStrategy = "head_tail"
+)
+
+// TokenizeInfo reports how an input was tokenized so callers can budget
+// context windows instead of discovering truncation after the fact.
+type TokenizeInfo struct {
+	TokenCount int  // total WordPiece tokens before truncation, excluding [CLS]/[SEP]
+	Truncated  bool // true if TokenCount exceeded the sequence's available budget
+}
+
+// Quantization selects which numeric precision of the MiniLM ONNX graph
+// to load.
+type Quantization string
+
+const (
+	// QuantizationNone loads the full-precision FP32 model.onnx.
+	QuantizationNone Quantization = "none"
+	// QuantizationDynamicInt8 loads the pre-quantized model_quantized.onnx
+	// variant, trading a small amount of cosine-similarity accuracy for
+	// roughly 4x smaller weights and 2x faster CPU inference.
+	QuantizationDynamicInt8 Quantization = "dynamic_int8"
+)
+
 // Config holds embeddings configuration
 type Config struct {
-	ModelPath string
-	Dimension int
+	ModelPath         string
+	Dimension         int
+	ExecutionProvider ExecutionProvider
+	Truncation        TruncationStrategy
+	Quantization      Quantization
+	// SessionPoolSize is the number of independent ONNX Runtime sessions
+	// to keep warm for concurrent GenerateEmbeddings calls. Defaults to
+	// runtime.NumCPU()/2 (minimum 1) when left zero.
+	SessionPoolSize int
 }
 
 // ServiceInfo contains information about the embeddings service
@@ -0,0 +1,255 @@
+package minilm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// bboltOpenTimeout bounds how long VectorStore waits to acquire the bbolt
+// file lock, so a stuck previous process fails fast instead of hanging
+// Initialize forever.
+const bboltOpenTimeout = 5 * time.Second
+
+// Record is one (id, text, metadata, embedding) tuple stored in a
+// VectorStore collection.
+type Record struct {
+	ID        string            `json:"id"`
+	Text      string            `json:"text"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Embedding []float32         `json:"embedding"`
+}
+
+// CollectionStats summarizes one collection for GET .../stats.
+type CollectionStats struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// VectorStore is a persistent, named-collection vector database: each
+// collection is a bbolt bucket of gob-free JSON-encoded Records, with an
+// in-memory HNSWIndex built over their embeddings for approximate
+// nearest-neighbor search. It turns the brute-force, caller-ships-every-
+// candidate SearchSimilar into a self-contained local RAG backend that
+// remembers what's been embedded across restarts.
+type VectorStore struct {
+	db  *bbolt.DB
+	dim int
+
+	mu      sync.Mutex
+	indexes map[string]*HNSWIndex
+}
+
+// NewVectorStore opens (creating if necessary) a bbolt database at path for
+// storing vectors of the given dimension.
+func NewVectorStore(path string, dim int) (*VectorStore, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: bboltOpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open vector store at %s: %w", path, err)
+	}
+	return &VectorStore{db: db, dim: dim, indexes: make(map[string]*HNSWIndex)}, nil
+}
+
+// Close releases the underlying bbolt database file.
+func (vs *VectorStore) Close() error {
+	return vs.db.Close()
+}
+
+// rebuildIndexLocked replays every record currently stored in collection
+// into a fresh HNSWIndex. Callers must hold vs.mu.
+func (vs *VectorStore) rebuildIndexLocked(collection string) (*HNSWIndex, error) {
+	idx := NewHNSWIndex(vs.dim)
+	err := vs.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(collection))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(_, v []byte) error {
+			var rec Record
+			if err := json.Unmarshal(v, &rec); err != nil {
+				return err
+			}
+			return idx.Add(rec.ID, rec.Embedding)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// indexFor returns collection's in-memory HNSW index, lazily rebuilding it
+// from disk the first time the collection is touched in this process.
+func (vs *VectorStore) indexFor(collection string) (*HNSWIndex, error) {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+
+	if idx, ok := vs.indexes[collection]; ok {
+		return idx, nil
+	}
+	idx, err := vs.rebuildIndexLocked(collection)
+	if err != nil {
+		return nil, err
+	}
+	vs.indexes[collection] = idx
+	return idx, nil
+}
+
+// Upsert stores rec in collection, persisting it to disk before indexing it.
+// Re-upserting an existing id replaces its stored record and rebuilds the
+// collection's index from disk, since HNSWIndex.Add has no in-place update
+// and re-adding the id directly would leave the old vector as a stale,
+// still-searchable duplicate.
+func (vs *VectorStore) Upsert(collection string, rec Record) error {
+	if rec.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if len(rec.Embedding) != vs.dim {
+		return fmt.Errorf("embedding has dimension %d, expected %d", len(rec.Embedding), vs.dim)
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode record: %w", err)
+	}
+
+	var existed bool
+	if err := vs.db.Update(func(tx *bbolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(collection))
+		if err != nil {
+			return err
+		}
+		existed = bucket.Get([]byte(rec.ID)) != nil
+		return bucket.Put([]byte(rec.ID), data)
+	}); err != nil {
+		return fmt.Errorf("failed to store record: %w", err)
+	}
+
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+
+	if !existed {
+		if idx, ok := vs.indexes[collection]; ok {
+			return idx.Add(rec.ID, rec.Embedding)
+		}
+	}
+
+	idx, err := vs.rebuildIndexLocked(collection)
+	if err != nil {
+		return err
+	}
+	vs.indexes[collection] = idx
+	return nil
+}
+
+// Delete removes id from collection and rebuilds its index to match.
+func (vs *VectorStore) Delete(collection, id string) error {
+	if err := vs.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(collection))
+		if bucket == nil {
+			return fmt.Errorf("unknown collection: %s", collection)
+		}
+		if bucket.Get([]byte(id)) == nil {
+			return fmt.Errorf("record not found: %s", id)
+		}
+		return bucket.Delete([]byte(id))
+	}); err != nil {
+		return err
+	}
+
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	idx, err := vs.rebuildIndexLocked(collection)
+	if err != nil {
+		return err
+	}
+	vs.indexes[collection] = idx
+	return nil
+}
+
+// Query returns the topK records in collection nearest queryEmbedding,
+// alongside their similarity scores, ranked best first.
+func (vs *VectorStore) Query(collection string, queryEmbedding []float32, topK int) ([]Record, []float32, error) {
+	idx, err := vs.indexFor(collection)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hits, err := idx.Search(queryEmbedding, topK)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	records := make([]Record, 0, len(hits))
+	scores := make([]float32, 0, len(hits))
+	err = vs.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(collection))
+		if bucket == nil {
+			return nil
+		}
+		for _, hit := range hits {
+			data := bucket.Get([]byte(hit.ID))
+			if data == nil {
+				continue
+			}
+			var rec Record
+			if err := json.Unmarshal(data, &rec); err != nil {
+				return err
+			}
+			records = append(records, rec)
+			scores = append(scores, hit.Score)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return records, scores, nil
+}
+
+// Stats reports the number of records stored in collection.
+func (vs *VectorStore) Stats(collection string) (CollectionStats, error) {
+	stats := CollectionStats{Name: collection}
+	err := vs.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(collection))
+		if bucket == nil {
+			return nil
+		}
+		stats.Count = bucket.Stats().KeyN
+		return nil
+	})
+	return stats, err
+}
+
+// SerializeForEmbedding renders text and metadata as a flat "key: value"
+// block, sorted by key for determinism, so a record with structured
+// metadata embeds with its field names as context rather than losing them -
+// the "serialize node to text, then embed" pattern RAG pipelines use over
+// structured rows/JSON documents. With no metadata it returns text as-is.
+func SerializeForEmbedding(text string, metadata map[string]string) string {
+	if len(metadata) == 0 {
+		return text
+	}
+
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s: %s\n", k, metadata[k])
+	}
+	if text != "" {
+		b.WriteString("text: ")
+		b.WriteString(text)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -4,30 +4,82 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 
 	"alice-backend/internal/config"
+	"alice-backend/internal/embedded"
+	"alice-backend/internal/metrics"
 	"alice-backend/internal/minilm"
 	"alice-backend/internal/piper"
+	"alice-backend/internal/services"
 	"alice-backend/internal/whisper"
+	"alice-backend/pkg/grpc/base"
 )
 
+// recoveryCheckInterval is how often Manager checks the backend monitor for
+// tripped circuits that are due for a re-initialization retry.
+const recoveryCheckInterval = 5 * time.Second
+
+// resourceSampleInterval is how often Manager records a process-wide
+// resource sample into the backend monitor.
+const resourceSampleInterval = 15 * time.Second
+
 // Manager coordinates all AI services
 type Manager struct {
 	config           *config.Config
 	sttService       *whisper.STTService
 	ttsService       *piper.TTSService
 	embeddingService *minilm.EmbeddingService
+	embeddingWorker  *base.SupervisedWorker // non-nil once Models.MiniLM.WorkerPath is configured and the worker started
+	vectorStore      *minilm.VectorStore
+	monitor          *services.BackendMonitor
+	metricsRecorder  *metrics.Recorder
 	mu               sync.RWMutex
+
+	// sttVariants/embeddingVariants hold additional named model backends
+	// lazily initialized on first request (see GetSTTServiceFor,
+	// GetEmbeddingServiceFor), on top of the default sttService/
+	// embeddingService above. Guarded by variantsMu rather than mu, since
+	// resolving a variant shouldn't block the default service's readers.
+	variantsMu        sync.Mutex
+	sttVariants       map[string]*sttVariantEntry
+	embeddingVariants map[string]*embeddingVariantEntry
+
+	// progressReporter, if set via SetProgressReporter, is passed to every
+	// whisper/piper AssetManager Manager constructs (default services and
+	// lazily-initialized variants alike), so asset download/extract/verify
+	// progress can be surfaced to e.g. an SSE subscriber.
+	progressReporter embedded.ProgressReporter
 }
 
 // NewManager creates a new model manager
 func NewManager(config *config.Config) *Manager {
 	return &Manager{
 		config: config,
+		monitor: services.NewBackendMonitor(
+			config.Features.ErrorRateThreshold,
+			config.Features.CircuitBreakerBackoff,
+			config.Features.CircuitBreakerMaxBackoff,
+		),
+		metricsRecorder:   metrics.NewRecorder(),
+		sttVariants:       make(map[string]*sttVariantEntry),
+		embeddingVariants: make(map[string]*embeddingVariantEntry),
 	}
 }
 
+// SetProgressReporter installs reporter as the destination for asset
+// download/extract/verify progress from every AssetManager Manager
+// constructs from this point on. Call it before Initialize so the reporter
+// is in place for the startup EnsureAssets run as well as later variant
+// initialization and reloads.
+func (m *Manager) SetProgressReporter(reporter embedded.ProgressReporter) {
+	m.progressReporter = reporter
+}
+
 // Initialize initializes all services based on configuration
 func (m *Manager) Initialize(ctx context.Context) error {
 	m.mu.Lock()
@@ -35,56 +87,275 @@ func (m *Manager) Initialize(ctx context.Context) error {
 
 	log.Println("Initializing model manager...")
 
-	// Initialize STT service if enabled
 	if m.config.Features.STT {
-		log.Println("Initializing STT service...")
-		sttConfig := &whisper.Config{
-			Language:       "en",
-			ModelPath:      "models/whisper-base.bin",
-			SampleRate:     16000,
-			VoiceThreshold: 0.02,
+		if err := m.initSTTLocked(ctx); err != nil {
+			return err
 		}
+	}
 
-		m.sttService = whisper.NewSTTService(sttConfig)
-		if err := m.sttService.Initialize(ctx); err != nil {
-			return fmt.Errorf("failed to initialize STT service: %w", err)
+	if m.config.Features.TTS {
+		if err := m.initTTSLocked(ctx); err != nil {
+			return err
 		}
-		log.Println("STT service initialized")
 	}
 
-	// Initialize TTS service if enabled
-	if m.config.Features.TTS {
-		log.Println("Initializing TTS service...")
-		ttsConfig := &piper.Config{
-			PiperPath: "", // Let ensurePiper set the correct OS-specific path
-			ModelPath: "models/piper",
-			Voice:     "en_US-amy-medium",
-			Speed:     1.0,
+	if m.config.Features.Embeddings {
+		if err := m.initEmbeddingsLocked(ctx); err != nil {
+			return err
 		}
+	}
+
+	go m.recoveryLoop(ctx)
+
+	log.Println("Model manager initialized successfully")
+	return nil
+}
+
+// recoveryLoop periodically re-initializes backends whose circuit breaker
+// has tripped (error rate over threshold, or an explicit services.Trip call)
+// once their exponential backoff has elapsed, and records a resource sample
+// for GET /api/status and GET /metrics. It runs for the lifetime of ctx.
+func (m *Manager) recoveryLoop(ctx context.Context) {
+	ticker := time.NewTicker(recoveryCheckInterval)
+	defer ticker.Stop()
+	lastResourceSample := time.Now()
 
-		m.ttsService = piper.NewTTSService(ttsConfig)
-		if err := m.ttsService.Initialize(ctx); err != nil {
-			return fmt.Errorf("failed to initialize TTS service: %w", err)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, name := range m.monitor.TrippedBackends() {
+				m.recoverBackend(ctx, name)
+			}
+			if time.Since(lastResourceSample) >= resourceSampleInterval {
+				m.monitor.SampleResources()
+				lastResourceSample = time.Now()
+			}
 		}
-		log.Println("TTS service initialized")
 	}
+}
 
-	// Initialize embeddings service if enabled
-	if m.config.Features.Embeddings {
-		log.Println("Initializing embeddings service...")
-		embeddingConfig := &minilm.Config{
-			ModelPath: m.config.Models.MiniLM.Path,
-			Dimension: 384,
+// recoverBackend re-initializes the named backend after its circuit
+// tripped, reporting the outcome back to the monitor so it can reset or
+// back off further.
+func (m *Manager) recoverBackend(ctx context.Context, name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	log.Printf("Attempting to recover backend %q after circuit trip", name)
+
+	var err error
+	switch name {
+	case "stt":
+		if m.sttService != nil {
+			_ = m.sttService.Shutdown(ctx)
 		}
+		err = m.initSTTLocked(ctx)
+	case "tts":
+		if m.ttsService != nil {
+			_ = m.ttsService.Shutdown(ctx)
+		}
+		err = m.initTTSLocked(ctx)
+	case "embeddings":
+		if m.embeddingService != nil {
+			_ = m.embeddingService.Shutdown(ctx)
+		}
+		err = m.initEmbeddingsLocked(ctx)
+	default:
+		log.Printf("Warning: recoverBackend called with unknown backend %q", name)
+		return
+	}
 
-		m.embeddingService = minilm.NewEmbeddingService(embeddingConfig)
-		if err := m.embeddingService.Initialize(ctx); err != nil {
-			return fmt.Errorf("failed to initialize embeddings service: %w", err)
+	if err != nil {
+		log.Printf("Warning: failed to recover backend %q: %v", name, err)
+		m.monitor.MarkRecoveryFailed(name)
+		return
+	}
+	m.monitor.MarkRecovered(name)
+	log.Printf("Backend %q recovered", name)
+}
+
+// initSTTLocked constructs and initializes the STT service. Callers must
+// already hold m.mu.
+func (m *Manager) initSTTLocked(ctx context.Context) error {
+	log.Println("Initializing STT service...")
+	sttConfig := &whisper.Config{
+		Language:         "en",
+		ModelPath:        "models/whisper-base.bin",
+		SampleRate:       16000,
+		VoiceThreshold:   0.02,
+		AssetMirrorURL:   m.config.Models.AssetMirrorURL,
+		ProgressReporter: m.progressReporter,
+	}
+
+	m.sttService = whisper.NewSTTService(sttConfig)
+	if err := m.sttService.Initialize(ctx); err != nil {
+		return fmt.Errorf("failed to initialize STT service: %w", err)
+	}
+	log.Println("STT service initialized")
+	return nil
+}
+
+// initTTSLocked constructs and initializes the TTS service. Callers must
+// already hold m.mu.
+func (m *Manager) initTTSLocked(ctx context.Context) error {
+	log.Println("Initializing TTS service...")
+	ttsConfig := &piper.Config{
+		PiperPath: "", // Let ensurePiper set the correct OS-specific path
+		ModelPath: "models/piper",
+		Voice:     "en_US-amy-medium",
+		Speed:     1.0,
+		Provider:  m.config.Models.Piper.Provider,
+		Coqui: piper.CoquiConfig{
+			BaseURL: m.config.Models.Piper.CoquiBaseURL,
+			Command: strings.Fields(m.config.Models.Piper.CoquiCommand),
+		},
+		OpenAI: piper.OpenAIConfig{
+			APIKey:  m.config.Models.Piper.OpenAIAPIKey,
+			BaseURL: m.config.Models.Piper.OpenAIBaseURL,
+		},
+		GoogleCloud: piper.GoogleCloudConfig{
+			APIKey: m.config.Models.Piper.GoogleCloudAPIKey,
+		},
+		AssetMirrorURL:   m.config.Models.AssetMirrorURL,
+		ProgressReporter: m.progressReporter,
+	}
+
+	m.ttsService = piper.NewTTSService(ttsConfig)
+	if err := m.ttsService.Initialize(ctx); err != nil {
+		return fmt.Errorf("failed to initialize TTS service: %w", err)
+	}
+	log.Println("TTS service initialized")
+	return nil
+}
+
+// initEmbeddingsLocked constructs and initializes the embeddings service,
+// and optionally its out-of-process worker. Callers must already hold m.mu.
+func (m *Manager) initEmbeddingsLocked(ctx context.Context) error {
+	log.Println("Initializing embeddings service...")
+	embeddingConfig := &minilm.Config{
+		ModelPath:         m.config.Models.MiniLM.Path,
+		Dimension:         384,
+		ExecutionProvider: minilm.ExecutionProvider(m.config.Models.MiniLM.ExecutionProvider),
+	}
+
+	m.embeddingService = minilm.NewEmbeddingService(embeddingConfig)
+	if err := m.embeddingService.Initialize(ctx); err != nil {
+		return fmt.Errorf("failed to initialize embeddings service: %w", err)
+	}
+	log.Println("Embeddings service initialized")
+
+	storePath := filepath.Join(m.config.Models.MiniLM.Path, "vectorstore.db")
+	vectorStore, err := minilm.NewVectorStore(storePath, embeddingConfig.Dimension)
+	if err != nil {
+		return fmt.Errorf("failed to open vector store: %w", err)
+	}
+	m.vectorStore = vectorStore
+
+	// Optionally also launch cmd/minilm-worker as a supervised
+	// subprocess over pkg/grpc/base, proving the "run out-of-process"
+	// half of the pluggable backend architecture described in
+	// proto/backend.proto. GetEmbeddingService still serves requests
+	// from the in-process embeddingService above, since switching
+	// every caller in internal/api to the remote worker would also
+	// need to give up DownloadModel/SelectModel (model-management
+	// methods outside the Backend RPC contract); GetEmbeddingWorker
+	// exposes the worker for callers that want it directly instead.
+	if workerPath := m.config.Models.MiniLM.WorkerPath; workerPath != "" {
+		socketPath := filepath.Join(os.TempDir(), fmt.Sprintf("alice-minilm-worker-%d.sock", os.Getpid()))
+		os.Remove(socketPath)
+		worker := base.NewSupervisedWorker(workerPath, []string{
+			"-socket", socketPath,
+			"-model-path", m.config.Models.MiniLM.Path,
+			"-execution-provider", m.config.Models.MiniLM.ExecutionProvider,
+		}, "unix", socketPath)
+
+		if _, err := worker.Start(ctx); err != nil {
+			log.Printf("Warning: failed to start minilm worker subprocess: %v", err)
+		} else {
+			m.embeddingWorker = worker
+			log.Printf("Embeddings worker subprocess listening on %s", socketPath)
 		}
-		log.Println("Embeddings service initialized")
 	}
 
-	log.Println("Model manager initialized successfully")
+	return nil
+}
+
+// Reload compares newCfg against the manager's current configuration and
+// selectively shuts down and re-initializes only the services whose
+// relevant settings changed, leaving unaffected services (and any
+// in-flight requests they're serving) untouched. It's the handler for
+// main's SIGHUP reload, and lets an operator pick up new TTS provider
+// credentials, a different Whisper model path, etc. without restarting
+// the process or dropping the listening socket.
+func (m *Manager) Reload(ctx context.Context, newCfg *config.Config) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	oldCfg := m.config
+
+	if newCfg.Features.STT != oldCfg.Features.STT || newCfg.Models.Whisper != oldCfg.Models.Whisper {
+		log.Println("Reload: STT configuration changed")
+		if m.sttService != nil {
+			if err := m.sttService.Shutdown(ctx); err != nil {
+				log.Printf("Warning: error shutting down STT service during reload: %v", err)
+			}
+			m.sttService = nil
+		}
+		m.config = newCfg
+		if newCfg.Features.STT {
+			if err := m.initSTTLocked(ctx); err != nil {
+				return fmt.Errorf("reload: %w", err)
+			}
+		}
+	}
+
+	if newCfg.Features.TTS != oldCfg.Features.TTS || newCfg.Models.Piper != oldCfg.Models.Piper {
+		log.Println("Reload: TTS configuration changed")
+		if m.ttsService != nil {
+			if err := m.ttsService.Shutdown(ctx); err != nil {
+				log.Printf("Warning: error shutting down TTS service during reload: %v", err)
+			}
+			m.ttsService = nil
+		}
+		m.config = newCfg
+		if newCfg.Features.TTS {
+			if err := m.initTTSLocked(ctx); err != nil {
+				return fmt.Errorf("reload: %w", err)
+			}
+		}
+	}
+
+	if newCfg.Features.Embeddings != oldCfg.Features.Embeddings || newCfg.Models.MiniLM != oldCfg.Models.MiniLM {
+		log.Println("Reload: embeddings configuration changed")
+		if m.embeddingWorker != nil {
+			if err := m.embeddingWorker.Stop(); err != nil {
+				log.Printf("Warning: error stopping embeddings worker during reload: %v", err)
+			}
+			m.embeddingWorker = nil
+		}
+		if m.embeddingService != nil {
+			if err := m.embeddingService.Shutdown(ctx); err != nil {
+				log.Printf("Warning: error shutting down embeddings service during reload: %v", err)
+			}
+			m.embeddingService = nil
+		}
+		if m.vectorStore != nil {
+			if err := m.vectorStore.Close(); err != nil {
+				log.Printf("Warning: error closing vector store during reload: %v", err)
+			}
+			m.vectorStore = nil
+		}
+		m.config = newCfg
+		if newCfg.Features.Embeddings {
+			if err := m.initEmbeddingsLocked(ctx); err != nil {
+				return fmt.Errorf("reload: %w", err)
+			}
+		}
+	}
+
+	m.config = newCfg
 	return nil
 }
 
@@ -109,6 +380,49 @@ func (m *Manager) GetEmbeddingService() *minilm.EmbeddingService {
 	return m.embeddingService
 }
 
+// GetVectorStore returns the persistent vector store backing the
+// /api/embeddings/collections routes, or nil if embeddings aren't enabled.
+func (m *Manager) GetVectorStore() *minilm.VectorStore {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.vectorStore
+}
+
+// GetEmbeddingWorker returns the supervised out-of-process embeddings
+// worker, if Models.MiniLM.WorkerPath was configured and it started
+// successfully, or nil otherwise.
+func (m *Manager) GetEmbeddingWorker() *base.SupervisedWorker {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.embeddingWorker
+}
+
+// GetMonitor returns the backend monitor tracking per-backend latency,
+// error rate, and circuit-breaker state.
+func (m *Manager) GetMonitor() *services.BackendMonitor {
+	return m.monitor
+}
+
+// GetMetricsRecorder returns the Prometheus request/inference metrics
+// recorder (see internal/metrics), shared across every request for the
+// lifetime of the process.
+func (m *Manager) GetMetricsRecorder() *metrics.Recorder {
+	return m.metricsRecorder
+}
+
+// GetRerankService returns a RerankService built on top of the current
+// embeddings service, or nil if embeddings aren't enabled/ready. It's built
+// fresh on each call rather than cached on Manager, since it's just a thin
+// wrapper around whichever embeddingService is currently live.
+func (m *Manager) GetRerankService() *minilm.RerankService {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.embeddingService == nil {
+		return nil
+	}
+	return minilm.NewRerankService(m.embeddingService)
+}
+
 // Shutdown gracefully shuts down all services
 func (m *Manager) Shutdown(ctx context.Context) error {
 	m.mu.Lock()
@@ -136,6 +450,32 @@ func (m *Manager) Shutdown(ctx context.Context) error {
 		}
 	}
 
+	if m.embeddingWorker != nil {
+		if err := m.embeddingWorker.Stop(); err != nil {
+			errs = append(errs, fmt.Errorf("embeddings worker shutdown error: %w", err))
+		}
+	}
+
+	if m.vectorStore != nil {
+		if err := m.vectorStore.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("vector store shutdown error: %w", err))
+		}
+		m.vectorStore = nil
+	}
+
+	m.variantsMu.Lock()
+	for name, entry := range m.sttVariants {
+		if err := entry.service.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("STT variant %q shutdown error: %w", name, err))
+		}
+	}
+	for name, entry := range m.embeddingVariants {
+		if err := entry.service.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("embeddings variant %q shutdown error: %w", name, err))
+		}
+	}
+	m.variantsMu.Unlock()
+
 	if len(errs) > 0 {
 		return fmt.Errorf("shutdown errors: %v", errs)
 	}
@@ -144,16 +484,23 @@ func (m *Manager) Shutdown(ctx context.Context) error {
 	return nil
 }
 
-// GetStatus returns the status of all services
+// GetStatus returns the status of all services, plus per-backend
+// latency/error-rate/circuit-breaker metrics when Features.EnableMetrics is
+// set.
 func (m *Manager) GetStatus() map[string]interface{} {
 	m.mu.RLock()
-	defer m.mu.RUnlock()
-
 	status := map[string]interface{}{
 		"stt":        m.sttService != nil && m.sttService.IsReady(),
 		"tts":        m.ttsService != nil && m.ttsService.IsReady(),
 		"embeddings": m.embeddingService != nil && m.embeddingService.IsReady(),
 	}
+	m.mu.RUnlock()
+
+	if m.config.Features.EnableMetrics {
+		backends, resource := m.monitor.Snapshot()
+		status["backends"] = backends
+		status["resources"] = resource
+	}
 
 	return status
 }
@@ -0,0 +1,145 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"alice-backend/internal/minilm"
+	"alice-backend/internal/whisper"
+)
+
+// sttVariantEntry is one lazily-initialized named STT backend, on top of
+// the default sttService configured at startup.
+type sttVariantEntry struct {
+	service  *whisper.STTService
+	lastUsed time.Time
+}
+
+// embeddingVariantEntry is one lazily-initialized named embeddings backend.
+type embeddingVariantEntry struct {
+	service  *minilm.EmbeddingService
+	lastUsed time.Time
+}
+
+// GetSTTServiceFor resolves the STT backend for a request. An empty name
+// returns the default service configured at startup (GetSTTService).
+// Otherwise name must match a Models.STTVariants entry; the matching model
+// is lazily initialized on first use and kept warm for subsequent requests,
+// subject to Models.MaxLoadedVariants LRU eviction. This is what lets a
+// client pick a model per-request via the X-Alice-Model header or a
+// /v1/{model}/stt/transcribe URL prefix without Manager loading every
+// configured variant eagerly at startup.
+func (m *Manager) GetSTTServiceFor(ctx context.Context, name string) (*whisper.STTService, error) {
+	if name == "" {
+		return m.GetSTTService(), nil
+	}
+
+	m.variantsMu.Lock()
+	defer m.variantsMu.Unlock()
+
+	if entry, ok := m.sttVariants[name]; ok {
+		entry.lastUsed = time.Now()
+		return entry.service, nil
+	}
+
+	modelPath, ok := m.config.Models.STTVariants[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown STT model variant: %s", name)
+	}
+
+	service := whisper.NewSTTService(&whisper.Config{
+		Language:         "en",
+		ModelPath:        modelPath,
+		SampleRate:       16000,
+		VoiceThreshold:   0.02,
+		AssetMirrorURL:   m.config.Models.AssetMirrorURL,
+		ProgressReporter: m.progressReporter,
+	})
+	if err := service.Initialize(ctx); err != nil {
+		return nil, fmt.Errorf("failed to initialize STT variant %q: %w", name, err)
+	}
+
+	m.sttVariants[name] = &sttVariantEntry{service: service, lastUsed: time.Now()}
+	m.evictSTTVariantsLocked(ctx)
+	return service, nil
+}
+
+// evictSTTVariantsLocked shuts down the least-recently-used STT variant
+// once Models.MaxLoadedVariants is exceeded. Callers must hold variantsMu.
+func (m *Manager) evictSTTVariantsLocked(ctx context.Context) {
+	limit := m.config.Models.MaxLoadedVariants
+	if limit <= 0 || len(m.sttVariants) <= limit {
+		return
+	}
+
+	var oldestName string
+	var oldestAt time.Time
+	for name, entry := range m.sttVariants {
+		if oldestName == "" || entry.lastUsed.Before(oldestAt) {
+			oldestName, oldestAt = name, entry.lastUsed
+		}
+	}
+
+	evicted := m.sttVariants[oldestName]
+	delete(m.sttVariants, oldestName)
+	if err := evicted.service.Shutdown(ctx); err != nil {
+		log.Printf("Warning: error shutting down evicted STT variant %q: %v", oldestName, err)
+	}
+}
+
+// GetEmbeddingServiceFor is GetSTTServiceFor's embeddings counterpart.
+func (m *Manager) GetEmbeddingServiceFor(ctx context.Context, name string) (*minilm.EmbeddingService, error) {
+	if name == "" {
+		return m.GetEmbeddingService(), nil
+	}
+
+	m.variantsMu.Lock()
+	defer m.variantsMu.Unlock()
+
+	if entry, ok := m.embeddingVariants[name]; ok {
+		entry.lastUsed = time.Now()
+		return entry.service, nil
+	}
+
+	modelPath, ok := m.config.Models.EmbeddingsVariants[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown embeddings model variant: %s", name)
+	}
+
+	service := minilm.NewEmbeddingService(&minilm.Config{
+		ModelPath: modelPath,
+		Dimension: 384,
+	})
+	if err := service.Initialize(ctx); err != nil {
+		return nil, fmt.Errorf("failed to initialize embeddings variant %q: %w", name, err)
+	}
+
+	m.embeddingVariants[name] = &embeddingVariantEntry{service: service, lastUsed: time.Now()}
+	m.evictEmbeddingVariantsLocked(ctx)
+	return service, nil
+}
+
+// evictEmbeddingVariantsLocked is evictSTTVariantsLocked's embeddings
+// counterpart. Callers must hold variantsMu.
+func (m *Manager) evictEmbeddingVariantsLocked(ctx context.Context) {
+	limit := m.config.Models.MaxLoadedVariants
+	if limit <= 0 || len(m.embeddingVariants) <= limit {
+		return
+	}
+
+	var oldestName string
+	var oldestAt time.Time
+	for name, entry := range m.embeddingVariants {
+		if oldestName == "" || entry.lastUsed.Before(oldestAt) {
+			oldestName, oldestAt = name, entry.lastUsed
+		}
+	}
+
+	evicted := m.embeddingVariants[oldestName]
+	delete(m.embeddingVariants, oldestName)
+	if err := evicted.service.Shutdown(ctx); err != nil {
+		log.Printf("Warning: error shutting down evicted embeddings variant %q: %v", oldestName, err)
+	}
+}
@@ -0,0 +1,204 @@
+package piper
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// maxArchiveEntrySize caps how large a single extracted file may be. The
+// archives this package deals with (a piper release build, a voice model)
+// are at most a few hundred MB, so anything larger is almost certainly a
+// decompression bomb rather than a legitimate asset.
+const maxArchiveEntrySize = 1 << 30 // 1GB
+
+// ArchiveEntry is one file or directory inside an archive, abstracted over
+// the underlying container format so extraction code doesn't need to branch
+// on zip vs. tar.
+type ArchiveEntry struct {
+	Name   string // path as stored in the archive, NOT yet safety-checked
+	IsDir  bool
+	Mode   os.FileMode
+	Reader io.Reader // nil for directories; valid only until the next Next() call
+}
+
+// ArchiveExtractor iterates an archive's entries one at a time, in archive
+// order, without extracting anything to disk itself - that's extractEntryTo's
+// job, so every format shares the same zip-slip and size-cap guards.
+type ArchiveExtractor interface {
+	Next() (*ArchiveEntry, error) // returns io.EOF once exhausted
+	Close() error
+}
+
+// openArchiveExtractor picks an ArchiveExtractor by archivePath's extension.
+func openArchiveExtractor(archivePath string) (ArchiveExtractor, error) {
+	switch {
+	case strings.HasSuffix(archivePath, ".zip"):
+		return newZipExtractor(archivePath)
+	case strings.HasSuffix(archivePath, ".tar.gz"), strings.HasSuffix(archivePath, ".tgz"):
+		return newTarExtractor(archivePath, func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) })
+	case strings.HasSuffix(archivePath, ".tar.bz2"), strings.HasSuffix(archivePath, ".tbz2"):
+		return newTarExtractor(archivePath, func(r io.Reader) (io.Reader, error) { return bzip2.NewReader(r), nil })
+	case strings.HasSuffix(archivePath, ".tar.xz"):
+		// compress/... has no xz decoder, and there's no pure-Go xz module
+		// vendored in this tree, so rather than silently mis-extracting (or
+		// fabricating a dependency that was never actually fetched), tar.xz
+		// is rejected outright until a real xz decoder is added.
+		return nil, fmt.Errorf("tar.xz archives are not supported in this build")
+	default:
+		return nil, fmt.Errorf("unsupported archive format: %s", archivePath)
+	}
+}
+
+type zipExtractor struct {
+	reader *zip.ReadCloser
+	index  int
+	rc     io.ReadCloser
+}
+
+func newZipExtractor(path string) (*zipExtractor, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	return &zipExtractor{reader: reader}, nil
+}
+
+func (z *zipExtractor) Next() (*ArchiveEntry, error) {
+	if z.rc != nil {
+		z.rc.Close()
+		z.rc = nil
+	}
+	if z.index >= len(z.reader.File) {
+		return nil, io.EOF
+	}
+	file := z.reader.File[z.index]
+	z.index++
+
+	if file.FileInfo().IsDir() {
+		return &ArchiveEntry{Name: file.Name, IsDir: true, Mode: file.Mode()}, nil
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	z.rc = rc
+	return &ArchiveEntry{Name: file.Name, Mode: file.Mode(), Reader: rc}, nil
+}
+
+func (z *zipExtractor) Close() error {
+	if z.rc != nil {
+		z.rc.Close()
+	}
+	return z.reader.Close()
+}
+
+type tarExtractor struct {
+	file    *os.File
+	decomp  io.Reader
+	closers []io.Closer
+	reader  *tar.Reader
+}
+
+func newTarExtractor(path string, decompress func(io.Reader) (io.Reader, error)) (*tarExtractor, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	decomp, err := decompress(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	t := &tarExtractor{file: file, decomp: decomp, reader: tar.NewReader(decomp)}
+	if closer, ok := decomp.(io.Closer); ok {
+		t.closers = append(t.closers, closer)
+	}
+	return t, nil
+}
+
+func (t *tarExtractor) Next() (*ArchiveEntry, error) {
+	header, err := t.reader.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	switch header.Typeflag {
+	case tar.TypeDir:
+		return &ArchiveEntry{Name: header.Name, IsDir: true, Mode: header.FileInfo().Mode()}, nil
+	default:
+		return &ArchiveEntry{Name: header.Name, Mode: header.FileInfo().Mode(), Reader: t.reader}, nil
+	}
+}
+
+func (t *tarExtractor) Close() error {
+	for _, c := range t.closers {
+		c.Close()
+	}
+	return t.file.Close()
+}
+
+// safeJoin resolves entryName against destDir and rejects the classic
+// zip-slip escape: an entry name containing ".." components or an absolute
+// path that, once cleaned, would land outside destDir.
+func safeJoin(destDir, entryName string) (string, error) {
+	cleaned := filepath.Clean(entryName)
+	if filepath.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("archive entry escapes destination directory: %q", entryName)
+	}
+
+	target := filepath.Join(destDir, cleaned)
+	if target != destDir && !strings.HasPrefix(target, destDir+string(filepath.Separator)) {
+		return "", fmt.Errorf("archive entry escapes destination directory: %q", entryName)
+	}
+	return target, nil
+}
+
+// extractEntryTo writes entry to outputPath, rejecting it if it (or the
+// decompressed payload) is larger than maxArchiveEntrySize, and preserving
+// the archive's own executable bit instead of unconditionally chmod-ing to
+// 0755.
+func extractEntryTo(entry *ArchiveEntry, outputPath string) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return err
+	}
+
+	outFile, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer outFile.Close()
+
+	limited := io.LimitReader(entry.Reader, maxArchiveEntrySize+1)
+	written, err := io.Copy(outFile, limited)
+	if err != nil {
+		return err
+	}
+	if written > maxArchiveEntrySize {
+		os.Remove(outputPath)
+		return fmt.Errorf("archive entry %q exceeds the %d byte extraction cap", entry.Name, maxArchiveEntrySize)
+	}
+
+	mode := entry.Mode
+	if mode == 0 {
+		mode = 0644
+	}
+	// On Windows there's no POSIX executable bit to preserve or that would
+	// matter, so leave whatever os.Create already applied.
+	if runtime.GOOS != "windows" {
+		if err := os.Chmod(outputPath, mode.Perm()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
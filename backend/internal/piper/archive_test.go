@@ -0,0 +1,130 @@
+package piper
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSafeJoinRejectsZipSlip asserts safeJoin rejects every classic zip-slip
+// vector instead of silently resolving an entry outside destDir.
+func TestSafeJoinRejectsZipSlip(t *testing.T) {
+	destDir := filepath.Join(string(filepath.Separator), "tmp", "alice-extract")
+
+	malicious := []string{
+		"../../../etc/passwd",
+		"../escape.txt",
+		"..",
+		"foo/../../bar",
+		"a/b/../../../c",
+	}
+	if filepath.Separator == '/' {
+		malicious = append(malicious, "/etc/passwd")
+	}
+
+	for _, name := range malicious {
+		t.Run(name, func(t *testing.T) {
+			if _, err := safeJoin(destDir, name); err == nil {
+				t.Errorf("safeJoin(%q, %q) = nil error, want an escape error", destDir, name)
+			}
+		})
+	}
+}
+
+// TestSafeJoinAllowsLegitimateEntries asserts safeJoin doesn't reject the
+// ordinary nested paths a real archive contains.
+func TestSafeJoinAllowsLegitimateEntries(t *testing.T) {
+	destDir := filepath.Join(string(filepath.Separator), "tmp", "alice-extract")
+
+	legit := []string{
+		"piper",
+		"piper.exe",
+		"lib/libonnxruntime.so",
+		"./voices/en_US-ryan-medium.onnx",
+	}
+
+	for _, name := range legit {
+		t.Run(name, func(t *testing.T) {
+			target, err := safeJoin(destDir, name)
+			if err != nil {
+				t.Fatalf("safeJoin(%q, %q) returned error: %v", destDir, name, err)
+			}
+			if !strings.HasPrefix(target, destDir) {
+				t.Errorf("safeJoin(%q, %q) = %q, want a path under %q", destDir, name, target, destDir)
+			}
+		})
+	}
+}
+
+// buildMaliciousZip writes an in-memory zip archive with a single entry
+// whose name is a zip-slip attempt, and returns its path on disk.
+func buildMaliciousZip(t *testing.T, dir, entryName string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create(entryName)
+	if err != nil {
+		t.Fatalf("zw.Create(%q): %v", entryName, err)
+	}
+	if _, err := w.Write([]byte("malicious payload")); err != nil {
+		t.Fatalf("writing zip entry: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("closing zip writer: %v", err)
+	}
+
+	archivePath := filepath.Join(dir, "malicious.zip")
+	if err := os.WriteFile(archivePath, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("writing archive to disk: %v", err)
+	}
+	return archivePath
+}
+
+// TestZipExtractorRejectsTraversalEndToEnd drives a crafted zip-slip archive
+// through the real newZipExtractor + safeJoin path (not just safeJoin in
+// isolation), and confirms no file ever lands outside the destination
+// directory.
+func TestZipExtractorRejectsTraversalEndToEnd(t *testing.T) {
+	vectors := []string{
+		"../../../etc/passwd",
+		"../escape.txt",
+		"a/../../b",
+	}
+
+	for _, entryName := range vectors {
+		t.Run(entryName, func(t *testing.T) {
+			srcDir := t.TempDir()
+			destDir := filepath.Join(t.TempDir(), "dest")
+			if err := os.MkdirAll(destDir, 0755); err != nil {
+				t.Fatalf("MkdirAll(destDir): %v", err)
+			}
+
+			archivePath := buildMaliciousZip(t, srcDir, entryName)
+
+			extractor, err := openArchiveExtractor(archivePath)
+			if err != nil {
+				t.Fatalf("openArchiveExtractor: %v", err)
+			}
+			defer extractor.Close()
+
+			entry, err := extractor.Next()
+			if err != nil {
+				t.Fatalf("extractor.Next(): %v", err)
+			}
+
+			if _, err := safeJoin(destDir, entry.Name); err == nil {
+				t.Errorf("safeJoin accepted malicious entry %q, want an escape error", entry.Name)
+			}
+
+			// Whether or not safeJoin is (mis)used, nothing should actually
+			// have been written outside destDir.
+			if _, err := os.Stat(filepath.Join(filepath.Dir(destDir), "escape.txt")); !os.IsNotExist(err) {
+				t.Errorf("traversal entry %q was written outside destDir", entry.Name)
+			}
+		})
+	}
+}
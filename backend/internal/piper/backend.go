@@ -0,0 +1,54 @@
+package piper
+
+import "context"
+
+// Backend is a pluggable speech-synthesis engine. TTSService's built-in
+// Piper machinery (binary/onnx, warm worker pool, voice catalog/downloads,
+// all implemented elsewhere in this package) is the default and only
+// Backend with on-disk voice models to manage; Config.Provider selects an
+// alternate Backend at startup so a deployment can trade Piper's
+// offline/free synthesis for a cloud provider's voices and quality without
+// any change to calling code.
+type Backend interface {
+	// Synthesize returns a WAV-encoded utterance for text in voice, at the
+	// given speed (1.0 = normal, 0 = the backend's own default).
+	Synthesize(ctx context.Context, text, voice string, speed float32) ([]byte, error)
+
+	// ListVoices returns the voices this backend can synthesize with right now.
+	ListVoices(ctx context.Context) ([]*Voice, error)
+
+	// Warmup performs whatever one-time setup the backend needs (checking
+	// credentials, spawning a subprocess) so the first real request isn't
+	// the one paying that cost. A Warmup error is logged, not fatal -
+	// synthesis still falls back to TTSService's placeholder audio.
+	Warmup(ctx context.Context) error
+
+	// Close releases any resources Warmup or Synthesize acquired.
+	Close() error
+}
+
+// newBackend constructs the Backend named by provider. An empty provider or
+// "piper" both mean "use TTSService's own built-in Piper machinery", which
+// is represented by a nil Backend rather than an adapter struct, since that
+// machinery is threaded through TTSService's private methods directly
+// (voice catalog, warm pool, onnx engine) instead of behind this interface.
+func newBackend(provider string, cfg *Config) (Backend, error) {
+	switch provider {
+	case "", "piper":
+		return nil, nil
+	case "coqui":
+		return newCoquiBackend(cfg.Coqui), nil
+	case "openai":
+		return newOpenAIBackend(cfg.OpenAI), nil
+	case "google":
+		return newGoogleCloudBackend(cfg.GoogleCloud), nil
+	default:
+		return nil, unsupportedProviderError(provider)
+	}
+}
+
+type unsupportedProviderError string
+
+func (e unsupportedProviderError) Error() string {
+	return "unsupported TTS provider: " + string(e)
+}
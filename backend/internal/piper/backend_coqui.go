@@ -0,0 +1,156 @@
+package piper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"time"
+)
+
+// CoquiConfig configures the Coqui/XTTS backend, which talks to a Coqui TTS
+// server (https://github.com/coqui-ai/TTS's tts-server) over HTTP rather
+// than embedding Coqui itself - it's a Python project with its own model
+// weights, so there's nothing to vendor into this Go binary.
+type CoquiConfig struct {
+	// BaseURL is the running tts-server's address, e.g. "http://127.0.0.1:5002".
+	BaseURL string
+
+	// Command, if set, is launched once on Warmup (and killed on Close) so
+	// Alice can own the server's lifecycle instead of requiring the operator
+	// to start it by hand - e.g. "tts-server --model_name tts_models/...".
+	// Leave empty to talk to a server started and managed elsewhere.
+	Command []string
+
+	// SpeakerWAV is the reference clip path passed to XTTS for voice
+	// cloning when Synthesize's voice argument is empty; empty disables
+	// cloning.
+	SpeakerWAV string
+}
+
+type coquiBackend struct {
+	cfg  CoquiConfig
+	proc *exec.Cmd
+	http *http.Client
+}
+
+func newCoquiBackend(cfg CoquiConfig) *coquiBackend {
+	return &coquiBackend{
+		cfg:  cfg,
+		http: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// Warmup launches the configured tts-server subprocess, if any, and waits
+// briefly for it to come up; a server that was already started externally
+// (cfg.Command empty) is only reachability-checked.
+func (b *coquiBackend) Warmup(ctx context.Context) error {
+	if len(b.cfg.Command) > 0 {
+		cmd := exec.CommandContext(context.Background(), b.cfg.Command[0], b.cfg.Command[1:]...)
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("failed to start coqui tts-server: %w", err)
+		}
+		b.proc = cmd
+		// tts-server can take tens of seconds to load a model before its
+		// HTTP port is live; give it a head start rather than failing the
+		// very first synthesis request.
+		time.Sleep(2 * time.Second)
+	}
+
+	if b.cfg.BaseURL == "" {
+		return fmt.Errorf("coqui backend has no base URL configured")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.cfg.BaseURL+"/", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := b.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("coqui tts-server unreachable at %s: %w", b.cfg.BaseURL, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Synthesize calls tts-server's /api/tts endpoint, which returns a raw WAV
+// body directly (no JSON envelope). voice is passed through as the
+// speaker_id query param; if it's empty and SpeakerWAV is configured, voice
+// cloning from that reference clip is used instead.
+func (b *coquiBackend) Synthesize(ctx context.Context, text, voice string, speed float32) ([]byte, error) {
+	if b.cfg.BaseURL == "" {
+		return nil, fmt.Errorf("coqui backend has no base URL configured")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.cfg.BaseURL+"/api/tts", nil)
+	if err != nil {
+		return nil, err
+	}
+	q := req.URL.Query()
+	q.Set("text", text)
+	if voice != "" {
+		q.Set("speaker_id", voice)
+	} else if b.cfg.SpeakerWAV != "" {
+		q.Set("speaker_wav", b.cfg.SpeakerWAV)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := b.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("coqui tts-server request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("coqui tts-server returned %d: %s", resp.StatusCode, body)
+	}
+	return body, nil
+}
+
+// ListVoices queries tts-server's /api/speakers endpoint for the speaker IDs
+// the currently-loaded model supports. Single-speaker models don't expose
+// this endpoint, in which case an empty (but non-error) list is returned -
+// Synthesize still works with an empty voice argument in that case.
+func (b *coquiBackend) ListVoices(ctx context.Context) ([]*Voice, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.cfg.BaseURL+"/api/speakers", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("coqui tts-server request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("coqui tts-server returned %d: %s", resp.StatusCode, body)
+	}
+
+	var speakers []string
+	if err := json.NewDecoder(resp.Body).Decode(&speakers); err != nil {
+		return nil, fmt.Errorf("failed to decode coqui speaker list: %w", err)
+	}
+
+	voices := make([]*Voice, 0, len(speakers))
+	for _, speaker := range speakers {
+		voices = append(voices, &Voice{Name: speaker, Description: "Coqui XTTS speaker: " + speaker})
+	}
+	return voices, nil
+}
+
+func (b *coquiBackend) Close() error {
+	if b.proc == nil || b.proc.Process == nil {
+		return nil
+	}
+	return b.proc.Process.Kill()
+}
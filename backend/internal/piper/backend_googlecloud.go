@@ -0,0 +1,183 @@
+package piper
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// GoogleCloudConfig configures the Google Cloud Text-to-Speech backend.
+//
+// This talks to the texttospeech.googleapis.com REST API directly over
+// net/http rather than through cloud.google.com/go/texttospeech/apiv1: that
+// module (and its transitive google.golang.org/genproto/grpc dependencies)
+// isn't vendored in this tree, and this sandbox has no network access to
+// `go get` it and regenerate go.sum. The REST API is the same service the
+// SDK wraps, so this gets the real feature rather than a stub - just
+// without the generated client types.
+type GoogleCloudConfig struct {
+	APIKey       string
+	LanguageCode string // defaults to "en-US" when empty
+}
+
+type googleCloudBackend struct {
+	cfg  GoogleCloudConfig
+	http *http.Client
+}
+
+func newGoogleCloudBackend(cfg GoogleCloudConfig) *googleCloudBackend {
+	return &googleCloudBackend{
+		cfg:  cfg,
+		http: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+func (b *googleCloudBackend) languageCode() string {
+	if b.cfg.LanguageCode != "" {
+		return b.cfg.LanguageCode
+	}
+	return "en-US"
+}
+
+func (b *googleCloudBackend) Warmup(ctx context.Context) error {
+	if b.cfg.APIKey == "" {
+		return fmt.Errorf("google cloud backend has no API key configured")
+	}
+	return nil
+}
+
+type googleSynthesizeRequest struct {
+	Input struct {
+		Text string `json:"text"`
+	} `json:"input"`
+	Voice struct {
+		LanguageCode string `json:"languageCode"`
+		Name         string `json:"name,omitempty"`
+	} `json:"voice"`
+	AudioConfig struct {
+		AudioEncoding string  `json:"audioEncoding"`
+		SpeakingRate  float32 `json:"speakingRate,omitempty"`
+	} `json:"audioConfig"`
+}
+
+type googleSynthesizeResponse struct {
+	AudioContent string `json:"audioContent"` // base64-encoded, per audioEncoding
+}
+
+// Synthesize requests LINEAR16 (raw PCM in a WAV container) so the result
+// needs no re-decoding before synthesizeWithBackend's own format conversion.
+func (b *googleCloudBackend) Synthesize(ctx context.Context, text, voice string, speed float32) ([]byte, error) {
+	if b.cfg.APIKey == "" {
+		return nil, fmt.Errorf("google cloud backend has no API key configured")
+	}
+
+	var reqBody googleSynthesizeRequest
+	reqBody.Input.Text = text
+	reqBody.Voice.LanguageCode = b.languageCode()
+	reqBody.Voice.Name = voice
+	reqBody.AudioConfig.AudioEncoding = "LINEAR16"
+	if speed > 0 {
+		reqBody.AudioConfig.SpeakingRate = speed
+	}
+
+	payload, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	url := "https://texttospeech.googleapis.com/v1/text:synthesize?key=" + b.cfg.APIKey
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("google cloud tts request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("google cloud tts returned %d: %s", resp.StatusCode, respBody)
+	}
+
+	var result googleSynthesizeResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode google cloud tts response: %w", err)
+	}
+
+	audio, err := base64.StdEncoding.DecodeString(result.AudioContent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode google cloud tts audio payload: %w", err)
+	}
+	return audio, nil
+}
+
+// ListVoices calls the voices:list endpoint, filtered to the configured
+// language so a multi-hundred-voice catalog doesn't drown out every other
+// backend's handful of voices in GetVoices/GetInfo.
+func (b *googleCloudBackend) ListVoices(ctx context.Context) ([]*Voice, error) {
+	if b.cfg.APIKey == "" {
+		return nil, fmt.Errorf("google cloud backend has no API key configured")
+	}
+
+	url := fmt.Sprintf("https://texttospeech.googleapis.com/v1/voices?key=%s&languageCode=%s", b.cfg.APIKey, b.languageCode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := b.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("google cloud tts request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("google cloud tts returned %d: %s", resp.StatusCode, respBody)
+	}
+
+	var listed struct {
+		Voices []struct {
+			Name          string   `json:"name"`
+			LanguageCodes []string `json:"languageCodes"`
+			SsmlGender    string   `json:"ssmlGender"`
+		} `json:"voices"`
+	}
+	if err := json.Unmarshal(respBody, &listed); err != nil {
+		return nil, fmt.Errorf("failed to decode google cloud voice list: %w", err)
+	}
+
+	voices := make([]*Voice, 0, len(listed.Voices))
+	for _, v := range listed.Voices {
+		lang := b.languageCode()
+		if len(v.LanguageCodes) > 0 {
+			lang = v.LanguageCodes[0]
+		}
+		voices = append(voices, &Voice{
+			Name:        v.Name,
+			Language:    lang,
+			Gender:      v.SsmlGender,
+			Description: "Google Cloud TTS voice: " + v.Name,
+		})
+	}
+	return voices, nil
+}
+
+func (b *googleCloudBackend) Close() error {
+	return nil
+}
@@ -0,0 +1,121 @@
+package piper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// OpenAIConfig configures the OpenAI TTS backend, which calls OpenAI's own
+// POST /v1/audio/speech - the same API internal/api/openai_tts.go exposes
+// for Alice's local Piper voices, used here the other way around as a
+// client.
+type OpenAIConfig struct {
+	APIKey  string
+	BaseURL string // defaults to "https://api.openai.com" when empty
+	Model   string // defaults to "tts-1" when empty
+}
+
+// openAIVoices is OpenAI's fixed set of built-in TTS voice names; the API
+// has no endpoint to list them, so they're hardcoded here the same way
+// fallbackVoices hardcodes Piper's built-ins when the catalog is
+// unreachable.
+var openAIVoices = []string{"alloy", "echo", "fable", "onyx", "nova", "shimmer"}
+
+type openAIBackend struct {
+	cfg  OpenAIConfig
+	http *http.Client
+}
+
+func newOpenAIBackend(cfg OpenAIConfig) *openAIBackend {
+	return &openAIBackend{
+		cfg:  cfg,
+		http: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+func (b *openAIBackend) baseURL() string {
+	if b.cfg.BaseURL != "" {
+		return b.cfg.BaseURL
+	}
+	return "https://api.openai.com"
+}
+
+func (b *openAIBackend) model() string {
+	if b.cfg.Model != "" {
+		return b.cfg.Model
+	}
+	return "tts-1"
+}
+
+// Warmup only checks that an API key was configured; there's no cheaper
+// OpenAI endpoint to round-trip against that wouldn't itself cost money.
+func (b *openAIBackend) Warmup(ctx context.Context) error {
+	if b.cfg.APIKey == "" {
+		return fmt.Errorf("openai backend has no API key configured")
+	}
+	return nil
+}
+
+// Synthesize requests WAV audio (response_format "wav") so the result needs
+// no re-decoding before synthesizeWithBackend's own format conversion step.
+func (b *openAIBackend) Synthesize(ctx context.Context, text, voice string, speed float32) ([]byte, error) {
+	if b.cfg.APIKey == "" {
+		return nil, fmt.Errorf("openai backend has no API key configured")
+	}
+	if voice == "" {
+		voice = "alloy"
+	}
+	if speed == 0 {
+		speed = 1.0
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"model":           b.model(),
+		"input":           text,
+		"voice":           voice,
+		"response_format": "wav",
+		"speed":           speed,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.baseURL()+"/v1/audio/speech", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+b.cfg.APIKey)
+
+	resp, err := b.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openai request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openai returned %d: %s", resp.StatusCode, respBody)
+	}
+	return respBody, nil
+}
+
+func (b *openAIBackend) ListVoices(ctx context.Context) ([]*Voice, error) {
+	voices := make([]*Voice, 0, len(openAIVoices))
+	for _, name := range openAIVoices {
+		voices = append(voices, &Voice{Name: name, Description: "OpenAI TTS voice: " + name})
+	}
+	return voices, nil
+}
+
+func (b *openAIBackend) Close() error {
+	return nil
+}
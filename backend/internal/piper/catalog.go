@@ -0,0 +1,170 @@
+package piper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// voiceManifestURL is the canonical voices.json manifest published alongside
+// every Piper voice release on HuggingFace. It lists every voice/quality/
+// language combination Piper publishes, with a per-file size and MD5 digest.
+const voiceManifestURL = "https://huggingface.co/rhasspy/piper-voices/resolve/main/voices.json"
+
+// voiceManifestBaseURL is where the files named in the manifest actually
+// live; each entry's .onnx path is relative to it.
+const voiceManifestBaseURL = "https://huggingface.co/rhasspy/piper-voices/resolve/main/"
+
+// voiceManifestTTL bounds how long a fetched manifest is reused before
+// VoiceCatalog re-fetches it, so a long-running process eventually notices
+// newly published voices without needing a restart.
+const voiceManifestTTL = 1 * time.Hour
+
+// manifestLanguage mirrors the "language" object voices.json nests inside
+// each voice entry.
+type manifestLanguage struct {
+	Code        string `json:"code"`
+	Family      string `json:"family"`
+	Region      string `json:"region"`
+	NameNative  string `json:"name_native"`
+	NameEnglish string `json:"name_english"`
+}
+
+// manifestFile describes one file belonging to a voice (its .onnx, its
+// .onnx.json, and sometimes a sample .mp3), keyed by repo-relative path.
+type manifestFile struct {
+	SizeBytes int64  `json:"size_bytes"`
+	MD5Digest string `json:"md5_digest"`
+}
+
+// manifestEntry is a single voice's record in voices.json.
+type manifestEntry struct {
+	Key      string                  `json:"key"`
+	Name     string                  `json:"name"`
+	Language manifestLanguage        `json:"language"`
+	Quality  string                  `json:"quality"`
+	Files    map[string]manifestFile `json:"files"`
+}
+
+// VoiceCatalog fetches and caches the upstream voices.json manifest,
+// exposing every voice Piper publishes rather than a small hardcoded list.
+type VoiceCatalog struct {
+	mu      sync.RWMutex
+	entries map[string]manifestEntry
+	fetched time.Time
+	client  *http.Client
+}
+
+// NewVoiceCatalog creates an empty catalog. The manifest is fetched lazily
+// on first use by List or Lookup, and re-fetched once voiceManifestTTL has
+// elapsed.
+func NewVoiceCatalog() *VoiceCatalog {
+	return &VoiceCatalog{client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Refresh fetches voices.json if the cached copy is missing or stale.
+func (c *VoiceCatalog) Refresh(ctx context.Context) error {
+	c.mu.RLock()
+	stale := time.Since(c.fetched) > voiceManifestTTL
+	c.mu.RUnlock()
+	if !stale {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, voiceManifestURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch voice manifest: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch voice manifest: HTTP %d", resp.StatusCode)
+	}
+
+	var raw map[string]manifestEntry
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return fmt.Errorf("failed to parse voice manifest: %w", err)
+	}
+
+	c.mu.Lock()
+	c.entries = raw
+	c.fetched = time.Now()
+	c.mu.Unlock()
+	return nil
+}
+
+// List returns every catalog voice, or only those matching lang (matched
+// against the manifest's language code, e.g. "de_DE", case-insensitively)
+// when lang is non-empty.
+func (c *VoiceCatalog) List(ctx context.Context, lang string) ([]*Voice, error) {
+	if err := c.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	voices := make([]*Voice, 0, len(c.entries))
+	for _, entry := range c.entries {
+		if lang != "" && !strings.EqualFold(entry.Language.Code, lang) {
+			continue
+		}
+		voices = append(voices, manifestEntryToVoice(entry))
+	}
+
+	sort.Slice(voices, func(i, j int) bool { return voices[i].Name < voices[j].Name })
+	return voices, nil
+}
+
+// Lookup returns the catalog entry for name, refreshing the manifest first
+// if it hasn't been fetched yet (or has gone stale).
+func (c *VoiceCatalog) Lookup(ctx context.Context, name string) (*Voice, error) {
+	if err := c.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown voice: %s", name)
+	}
+	return manifestEntryToVoice(entry), nil
+}
+
+// manifestEntryToVoice converts one voices.json record into this package's
+// Voice type, pulling the .onnx file's size and MD5 digest out of its Files
+// map and building its download URL relative to the manifest's own repo.
+func manifestEntryToVoice(entry manifestEntry) *Voice {
+	v := &Voice{
+		Name:        entry.Key,
+		Language:    entry.Language.Code,
+		Speaker:     entry.Name,
+		Quality:     entry.Quality,
+		SampleRate:  22050,
+		Description: fmt.Sprintf("%s (%s, %s)", entry.Name, entry.Language.NameEnglish, entry.Quality),
+	}
+
+	for path, file := range entry.Files {
+		if !strings.HasSuffix(path, ".onnx") {
+			continue
+		}
+		v.DownloadURL = voiceManifestBaseURL + path
+		v.Size = file.SizeBytes
+		v.MD5 = file.MD5Digest
+		break
+	}
+
+	return v
+}
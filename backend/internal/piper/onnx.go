@@ -0,0 +1,364 @@
+package piper
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// onnxVoiceConfig mirrors the subset of a Piper .onnx.json sidecar this
+// engine needs: the phoneme vocabulary, sample rate, and the VITS
+// inference scalars (noise_scale/length_scale/noise_w) the model was
+// exported with.
+type onnxVoiceConfig struct {
+	Audio struct {
+		SampleRate int `json:"sample_rate"`
+	} `json:"audio"`
+	Inference struct {
+		NoiseScale  float32 `json:"noise_scale"`
+		LengthScale float32 `json:"length_scale"`
+		NoiseW      float32 `json:"noise_w"`
+	} `json:"inference"`
+	ESpeak struct {
+		Voice string `json:"voice"`
+	} `json:"espeak"`
+	PhonemeIDMap map[string][]int64 `json:"phoneme_id_map"`
+}
+
+// onnxVoice is one voice's ONNX Runtime session, kept alive for the
+// lifetime of the process so synthesizing a sentence never has to reload
+// or re-parse the model.
+type onnxVoice struct {
+	session *ort.DynamicAdvancedSession
+	config  *onnxVoiceConfig
+}
+
+// onnxEngine runs Piper's VITS models directly through ONNX Runtime,
+// in-process, instead of shelling out to the piper binary. It caches one
+// onnxVoice per voice name so a model is only ever loaded once.
+type onnxEngine struct {
+	mu     sync.Mutex
+	voices map[string]*onnxVoice
+}
+
+// onnxRuntimeVersion pins the ONNX Runtime release downloaded when nothing
+// has pointed onnxruntime_go at a shared library yet. Kept in sync with
+// minilm's own ensureORTSharedLibVariant so both services share one cached
+// copy of the library when both features are enabled.
+const onnxRuntimeVersion = "v1.22.0"
+
+// newOnnxEngine starts the shared ONNX Runtime environment. onnxruntime_go
+// keeps a single process-wide environment and shared-library path, so if
+// another service (e.g. the MiniLM embeddings backend) already initialized
+// it, this reuses that rather than loading a second copy.
+func newOnnxEngine(ctx context.Context) (*onnxEngine, error) {
+	if !ort.IsInitialized() {
+		libPath, err := ensureONNXRuntimeLib(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("onnxruntime lib: %w", err)
+		}
+		ort.SetSharedLibraryPath(libPath)
+		if err := ort.InitializeEnvironment(); err != nil {
+			return nil, fmt.Errorf("failed to initialize ONNX Runtime: %w", err)
+		}
+	}
+	return &onnxEngine{voices: make(map[string]*onnxVoice)}, nil
+}
+
+// ensureONNXRuntimeLib downloads (and caches under os.TempDir(), the same
+// location minilm.ensureORTSharedLibVariant uses) the plain CPU ONNX Runtime
+// shared library for the current platform. Piper's VITS models are small
+// enough that GPU acceleration isn't worth the extra download/complexity
+// minilm's execution-provider selection carries for embeddings.
+func ensureONNXRuntimeLib(ctx context.Context) (string, error) {
+	versionDir := filepath.Join(os.TempDir(), "onnxruntime", onnxRuntimeVersion, "cpu")
+	if err := os.MkdirAll(versionDir, 0o755); err != nil {
+		return "", err
+	}
+	verNum := onnxRuntimeVersion[1:]
+
+	switch runtime.GOOS {
+	case "windows":
+		dll := filepath.Join(versionDir, "onnxruntime.dll")
+		if fileExistsOnnx(dll) {
+			return dll, nil
+		}
+		url := "https://github.com/microsoft/onnxruntime/releases/download/" + onnxRuntimeVersion + "/onnxruntime-win-x64-" + verNum + ".zip"
+		zipPath := filepath.Join(versionDir, "ort.zip")
+		if err := downloadONNXRuntimeArchive(ctx, url, zipPath); err != nil {
+			return "", err
+		}
+		if err := unzipOneOnnx(zipPath, versionDir, "onnxruntime.dll"); err != nil {
+			return "", err
+		}
+		return dll, nil
+
+	case "darwin":
+		dylib := filepath.Join(versionDir, "libonnxruntime.dylib")
+		if fileExistsOnnx(dylib) {
+			return dylib, nil
+		}
+		url := "https://github.com/microsoft/onnxruntime/releases/download/" + onnxRuntimeVersion + "/onnxruntime-osx-universal2-" + verNum + ".tgz"
+		tgz := filepath.Join(versionDir, "ort.tgz")
+		if err := downloadONNXRuntimeArchive(ctx, url, tgz); err != nil {
+			return "", err
+		}
+		if err := untarOneOnnx(tgz, versionDir, "libonnxruntime.dylib"); err != nil {
+			return "", err
+		}
+		return dylib, nil
+
+	case "linux":
+		so := filepath.Join(versionDir, "libonnxruntime.so")
+		if fileExistsOnnx(so) {
+			return so, nil
+		}
+		url := "https://github.com/microsoft/onnxruntime/releases/download/" + onnxRuntimeVersion + "/onnxruntime-linux-x64-" + verNum + ".tgz"
+		tgz := filepath.Join(versionDir, "ort.tgz")
+		if err := downloadONNXRuntimeArchive(ctx, url, tgz); err != nil {
+			return "", err
+		}
+		if err := untarOneOnnx(tgz, versionDir, "libonnxruntime.so"); err != nil {
+			return "", err
+		}
+		return so, nil
+
+	default:
+		return "", fmt.Errorf("unsupported platform for ONNX Runtime: %s", runtime.GOOS)
+	}
+}
+
+func downloadONNXRuntimeArchive(ctx context.Context, url, dst string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: 240 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download %s: HTTP %d", url, resp.StatusCode)
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, resp.Body)
+	return err
+}
+
+func unzipOneOnnx(zipPath, dstDir, wanted string) error {
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if filepath.Base(f.Name) != wanted {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+
+		out, err := os.Create(filepath.Join(dstDir, wanted))
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+
+		_, err = io.Copy(out, rc)
+		return err
+	}
+	return fmt.Errorf("file %s not found in %s", wanted, zipPath)
+}
+
+func untarOneOnnx(tgzPath, dstDir, wanted string) error {
+	f, err := os.Open(tgzPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if filepath.Base(hdr.Name) != wanted || hdr.FileInfo().IsDir() {
+			continue
+		}
+
+		out, err := os.Create(filepath.Join(dstDir, wanted))
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+
+		_, err = io.Copy(out, tr)
+		return err
+	}
+	return fmt.Errorf("file %s not found in %s", wanted, tgzPath)
+}
+
+func fileExistsOnnx(p string) bool {
+	_, err := os.Stat(p)
+	return err == nil
+}
+
+// loadVoice returns the cached session for voiceName, loading modelPath
+// (and its accompanying modelPath+".json" config) on first use.
+func (e *onnxEngine) loadVoice(voiceName, modelPath string) (*onnxVoice, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if v, ok := e.voices[voiceName]; ok {
+		return v, nil
+	}
+
+	configData, err := os.ReadFile(modelPath + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read voice config: %w", err)
+	}
+
+	var cfg onnxVoiceConfig
+	if err := json.Unmarshal(configData, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse voice config: %w", err)
+	}
+
+	session, err := ort.NewDynamicAdvancedSession(modelPath,
+		[]string{"input", "input_lengths", "scales"},
+		[]string{"output"}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ONNX model %s: %w", modelPath, err)
+	}
+
+	v := &onnxVoice{session: session, config: &cfg}
+	e.voices[voiceName] = v
+	return v, nil
+}
+
+// synthesize phonemizes text with piper-phonemize, runs the voice's VITS
+// model over the resulting phoneme IDs, and returns 16-bit PCM samples
+// alongside the model's native sample rate.
+func (e *onnxEngine) synthesize(voiceName, modelPath, text string, speed float32) ([]int16, int, error) {
+	v, err := e.loadVoice(voiceName, modelPath)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	phonemeIDs, err := phonemize(text, v.config.ESpeak.Voice, v.config.PhonemeIDMap)
+	if err != nil {
+		return nil, 0, fmt.Errorf("phonemization failed: %w", err)
+	}
+	if len(phonemeIDs) == 0 {
+		return nil, 0, fmt.Errorf("text produced no phonemes")
+	}
+
+	lengthScale := v.config.Inference.LengthScale
+	if lengthScale == 0 {
+		lengthScale = 1.0
+	}
+	if speed > 0 {
+		lengthScale = 1.0 / speed
+	}
+	noiseScale := v.config.Inference.NoiseScale
+	noiseW := v.config.Inference.NoiseW
+
+	inputTensor, err := ort.NewTensor(ort.NewShape(1, int64(len(phonemeIDs))), phonemeIDs)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build input tensor: %w", err)
+	}
+	defer inputTensor.Destroy()
+
+	lengthsTensor, err := ort.NewTensor(ort.NewShape(1), []int64{int64(len(phonemeIDs))})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build input_lengths tensor: %w", err)
+	}
+	defer lengthsTensor.Destroy()
+
+	scalesTensor, err := ort.NewTensor(ort.NewShape(3), []float32{noiseScale, lengthScale, noiseW})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build scales tensor: %w", err)
+	}
+	defer scalesTensor.Destroy()
+
+	// The waveform's length depends on the phonemes, so the output is left
+	// nil for ONNX Runtime to allocate at its real shape.
+	inputs := []ort.Value{inputTensor, lengthsTensor, scalesTensor}
+	outputs := []ort.Value{nil}
+	if err := v.session.Run(inputs, outputs); err != nil {
+		return nil, 0, fmt.Errorf("ONNX Runtime inference failed: %w", err)
+	}
+	outputTensor, ok := outputs[0].(*ort.Tensor[float32])
+	if !ok {
+		return nil, 0, fmt.Errorf("unexpected ONNX output type %T", outputs[0])
+	}
+	defer outputTensor.Destroy()
+
+	return floatsToPCM16(outputTensor.GetData()), v.config.Audio.SampleRate, nil
+}
+
+// floatsToPCM16 converts VITS's [-1, 1] float32 waveform output to 16-bit
+// PCM, clamping anything that overshoots the range rather than wrapping.
+func floatsToPCM16(samples []float32) []int16 {
+	pcm := make([]int16, len(samples))
+	for i, f := range samples {
+		scaled := math.Round(float64(f) * 32767)
+		if scaled > 32767 {
+			scaled = 32767
+		} else if scaled < -32768 {
+			scaled = -32768
+		}
+		pcm[i] = int16(scaled)
+	}
+	return pcm
+}
+
+// Close releases the cached ONNX Runtime sessions.
+func (e *onnxEngine) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for name, v := range e.voices {
+		if err := v.session.Destroy(); err != nil {
+			return fmt.Errorf("failed to release session for voice %s: %w", name, err)
+		}
+	}
+	e.voices = make(map[string]*onnxVoice)
+	return nil
+}
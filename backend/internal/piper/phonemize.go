@@ -0,0 +1,85 @@
+//go:build piper_phonemize
+
+package piper
+
+/*
+#cgo pkg-config: espeak-ng
+#include <espeak-ng/speak_lib.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// espeakInit ensures espeak-ng's synchronous text-to-phonemes mode is
+// initialized exactly once for the process; piper-phonemize uses the same
+// global-engine pattern internally.
+var espeakInit sync.Once
+var espeakInitErr error
+
+func ensureEspeakInitialized() error {
+	espeakInit.Do(func() {
+		rc := C.espeak_Initialize(C.AUDIO_OUTPUT_SYNCHRONOUS, 0, nil, 0)
+		if rc < 0 {
+			espeakInitErr = fmt.Errorf("espeak_Initialize failed with code %d", rc)
+		}
+	})
+	return espeakInitErr
+}
+
+// phonemize converts text into the int64 phoneme ID sequence a Piper VITS
+// model expects, via espeak-ng's IPA phonemizer (the same one
+// piper-phonemize wraps) and the voice's phoneme_id_map.
+//
+// Piper interleaves a pad symbol between every phoneme (and wraps the whole
+// sequence in BOS/EOS pad symbols) because the model was trained that way;
+// skipping it produces garbled, rushed-sounding audio even though the
+// model still technically runs.
+func phonemize(text, espeakVoice string, idMap map[string][]int64) ([]int64, error) {
+	if err := ensureEspeakInitialized(); err != nil {
+		return nil, err
+	}
+
+	if espeakVoice == "" {
+		espeakVoice = "en-us"
+	}
+	cVoice := C.CString(espeakVoice)
+	defer C.free(unsafe.Pointer(cVoice))
+	if C.espeak_SetVoiceByName(cVoice) != 0 {
+		return nil, fmt.Errorf("espeak-ng has no voice %q", espeakVoice)
+	}
+
+	cText := C.CString(text)
+	defer C.free(unsafe.Pointer(cText))
+	textPtr := unsafe.Pointer(cText)
+
+	var phonemeRunes []rune
+	for textPtr != nil {
+		out := C.espeak_TextToPhonemes(&textPtr, C.espeakCHARS_UTF8, C.espeakPHONEMES_IPA)
+		if out == nil {
+			break
+		}
+		phonemeRunes = append(phonemeRunes, []rune(C.GoString(out))...)
+	}
+
+	ids := make([]int64, 0, len(phonemeRunes)*2+2)
+	appendSymbol := func(symbol string) {
+		if mapped, ok := idMap[symbol]; ok {
+			ids = append(ids, mapped...)
+		}
+	}
+
+	appendSymbol("^")
+	appendSymbol("_")
+	for _, r := range phonemeRunes {
+		appendSymbol(string(r))
+		appendSymbol("_")
+	}
+	appendSymbol("$")
+
+	return ids, nil
+}
@@ -0,0 +1,15 @@
+//go:build !piper_phonemize
+
+package piper
+
+import "fmt"
+
+// phonemize is a no-op placeholder for builds without the piper_phonemize
+// tag (the default), since the real implementation depends on espeak-ng
+// headers that aren't available in every build environment. The onnx
+// backend still loads fine without it; synthesize calls simply fail and
+// Synthesize falls back to placeholder audio, the same way it already does
+// when the piper binary itself is missing.
+func phonemize(text, espeakVoice string, idMap map[string][]int64) ([]int64, error) {
+	return nil, fmt.Errorf("piper phonemizer not compiled in; rebuild with -tags piper_phonemize")
+}
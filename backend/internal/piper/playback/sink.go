@@ -0,0 +1,36 @@
+// Package playback provides optional direct-to-speaker audio output for
+// Piper synthesis, bypassing the WAV-bytes-over-HTTP-to-<audio> path so a
+// desktop assistant can start speaking within a couple hundred milliseconds
+// of the first phonemes being generated instead of waiting on the browser's
+// audio pipeline.
+package playback
+
+import "fmt"
+
+// Sink is a live connection to an OS audio output device. Implementations
+// are platform-specific (see sink_linux.go, sink_darwin.go, sink_windows.go)
+// and only compiled in when built with -tags piper_playback; without that
+// tag, NewSink returns an error the same way piper's onnx phonemizer does
+// when piper_phonemize isn't set.
+type Sink interface {
+	// Open prepares the sink to accept samples at the given sample rate and
+	// channel count. It must be called before Write.
+	Open(sampleRate, channels int) error
+
+	// Write sends PCM samples to the device. It blocks until the
+	// implementation's internal buffer has room, providing natural
+	// backpressure for a streaming caller.
+	Write(samples []int16) error
+
+	// Drain blocks until every sample already handed to Write has finished
+	// playing.
+	Drain() error
+
+	// Close releases the sink's resources. Pending audio that hasn't been
+	// written is discarded; call Drain first to let it finish playing.
+	Close() error
+}
+
+// ErrNotCompiled is returned by NewSink when the binary wasn't built with
+// -tags piper_playback.
+var ErrNotCompiled = fmt.Errorf("direct audio playback not compiled in; rebuild with -tags piper_playback")
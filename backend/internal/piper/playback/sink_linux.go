@@ -0,0 +1,75 @@
+//go:build linux && piper_playback
+
+package playback
+
+/*
+#cgo pkg-config: libpulse-simple
+#include <pulse/simple.h>
+#include <pulse/error.h>
+#include <stdlib.h>
+
+static pa_simple *open_playback_stream(int rate, int channels, int *rerror) {
+	pa_sample_spec spec;
+	spec.format = PA_SAMPLE_S16LE;
+	spec.rate = (uint32_t)rate;
+	spec.channels = (uint8_t)channels;
+	return pa_simple_new(NULL, "Alice", PA_STREAM_PLAYBACK, NULL, "tts", &spec, NULL, NULL, rerror);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// pulseSink streams PCM to the default output device over PulseAudio's
+// native protocol via libpulse-simple, the same minimal client API most
+// command-line PulseAudio players use.
+type pulseSink struct {
+	stream *C.pa_simple
+}
+
+// NewSink opens a connection to the system's PulseAudio server.
+func NewSink() (Sink, error) {
+	return &pulseSink{}, nil
+}
+
+func (s *pulseSink) Open(sampleRate, channels int) error {
+	var rerror C.int
+	stream := C.open_playback_stream(C.int(sampleRate), C.int(channels), &rerror)
+	if stream == nil {
+		return fmt.Errorf("pa_simple_new failed: %s", C.GoString(C.pa_strerror(rerror)))
+	}
+	s.stream = stream
+	return nil
+}
+
+func (s *pulseSink) Write(samples []int16) error {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	var rerror C.int
+	ok := C.pa_simple_write(s.stream, unsafe.Pointer(&samples[0]), C.size_t(len(samples)*2), &rerror)
+	if ok < 0 {
+		return fmt.Errorf("pa_simple_write failed: %s", C.GoString(C.pa_strerror(rerror)))
+	}
+	return nil
+}
+
+func (s *pulseSink) Drain() error {
+	var rerror C.int
+	if C.pa_simple_drain(s.stream, &rerror) < 0 {
+		return fmt.Errorf("pa_simple_drain failed: %s", C.GoString(C.pa_strerror(rerror)))
+	}
+	return nil
+}
+
+func (s *pulseSink) Close() error {
+	if s.stream != nil {
+		C.pa_simple_free(s.stream)
+		s.stream = nil
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !piper_playback
+
+package playback
+
+// NewSink is a no-op placeholder for builds without the piper_playback tag
+// (the default), since every real implementation depends on a native audio
+// library that isn't available in every build environment. Callers should
+// fall back to returning synthesized audio as bytes, the same way
+// TTSService.Synthesize already does when the onnx/piper backends are
+// unavailable.
+func NewSink() (Sink, error) {
+	return nil, ErrNotCompiled
+}
@@ -0,0 +1,282 @@
+package piper
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	piperPoolMaxWorkers  = 4                // concurrent warm piper processes, one per in-use voice
+	piperPoolIdleTimeout = 2 * time.Minute  // how long an unused worker stays warm before being reaped
+	piperPoolSweepEvery  = 30 * time.Second // how often the reaper checks for idle workers
+	piperWorkerWriteWait = 30 * time.Second // how long a synthesize call waits for its output file to appear
+)
+
+// piperWorkerPool keeps one warm, long-lived piper process per voice instead
+// of spawning (and paying process-startup cost for) a new one on every
+// synthesize call. Each worker is fed one line of text at a time over stdin
+// and writes the resulting WAV to --output_dir, which is how piper itself
+// supports a persistent "synthesize many utterances in one process"
+// lifecycle; --length_scale is a piper startup flag, so a worker can't honor
+// a per-call speed override, and synthesizeWithPiper falls back to spawning
+// a one-shot process in that case.
+type piperWorkerPool struct {
+	mu      sync.Mutex
+	workers map[string]*piperWorker
+	stop    chan struct{}
+	once    sync.Once
+}
+
+// piperWorker is a single warm piper process dedicated to one voice, started
+// at a fixed speed that can't change for the life of the process.
+type piperWorker struct {
+	mu        sync.Mutex
+	cmd       *exec.Cmd
+	stdin     *os.File
+	outputDir string
+	speed     float32
+	lastUsed  time.Time
+	closed    bool
+}
+
+func newPiperWorkerPool() *piperWorkerPool {
+	p := &piperWorkerPool{
+		workers: make(map[string]*piperWorker),
+		stop:    make(chan struct{}),
+	}
+	go p.reapIdleLoop()
+	return p
+}
+
+func (p *piperWorkerPool) reapIdleLoop() {
+	ticker := time.NewTicker(piperPoolSweepEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.reapIdle()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+func (p *piperWorkerPool) reapIdle() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for voice, w := range p.workers {
+		w.mu.Lock()
+		idle := time.Since(w.lastUsed) > piperPoolIdleTimeout
+		w.mu.Unlock()
+		if idle {
+			w.close()
+			delete(p.workers, voice)
+		}
+	}
+}
+
+// get returns the warm worker for voice, starting one if needed. If the
+// pool is already at piperPoolMaxWorkers and voice isn't among them, the
+// least-recently-used worker is evicted to make room. speed is baked into
+// the worker at startup (piper's --length_scale can't change mid-process),
+// so callers asking for a different speed than the pool's worker was
+// started with must fall back to a one-shot process instead of calling get.
+func (p *piperWorkerPool) get(ctx context.Context, piperPath, espeakDataPath, modelFile, voice string, speed float32) (*piperWorker, error) {
+	p.mu.Lock()
+	if w, ok := p.workers[voice]; ok && !w.isClosed() {
+		p.mu.Unlock()
+		return w, nil
+	}
+
+	if len(p.workers) >= piperPoolMaxWorkers {
+		p.evictLRULocked()
+	}
+
+	w, err := startPiperWorker(ctx, piperPath, espeakDataPath, modelFile, speed)
+	if err != nil {
+		p.mu.Unlock()
+		return nil, err
+	}
+	p.workers[voice] = w
+	p.mu.Unlock()
+	return w, nil
+}
+
+// evictLRULocked closes and removes the least-recently-used worker. Callers
+// must hold p.mu.
+func (p *piperWorkerPool) evictLRULocked() {
+	var lruVoice string
+	var lruAt time.Time
+	for voice, w := range p.workers {
+		w.mu.Lock()
+		used := w.lastUsed
+		w.mu.Unlock()
+		if lruVoice == "" || used.Before(lruAt) {
+			lruVoice, lruAt = voice, used
+		}
+	}
+	if lruVoice != "" {
+		p.workers[lruVoice].close()
+		delete(p.workers, lruVoice)
+	}
+}
+
+// Close shuts down every warm worker and stops the reaper. Safe to call
+// more than once.
+func (p *piperWorkerPool) Close() {
+	p.once.Do(func() {
+		close(p.stop)
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		for voice, w := range p.workers {
+			w.close()
+			delete(p.workers, voice)
+		}
+	})
+}
+
+func startPiperWorker(ctx context.Context, piperPath, espeakDataPath, modelFile string, speed float32) (*piperWorker, error) {
+	outputDir, err := os.MkdirTemp("", "piper-worker-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create worker output dir: %w", err)
+	}
+
+	args := []string{"--model", modelFile, "--output_dir", outputDir}
+	if speed > 0 && speed != 1.0 {
+		args = append(args, "--length_scale", fmt.Sprintf("%.2f", 1.0/speed))
+	}
+
+	cmd := exec.Command(piperPath, args...)
+	cmd.Env = append(os.Environ(), "ESPEAK_DATA_PATH="+espeakDataPath)
+
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		os.RemoveAll(outputDir)
+		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+	cmd.Stdin = stdinR
+
+	if err := cmd.Start(); err != nil {
+		stdinR.Close()
+		stdinW.Close()
+		os.RemoveAll(outputDir)
+		return nil, fmt.Errorf("failed to start piper worker: %w", err)
+	}
+	stdinR.Close() // the child keeps its own copy; we only need the write end
+
+	return &piperWorker{
+		cmd:       cmd,
+		stdin:     stdinW,
+		outputDir: outputDir,
+		speed:     speed,
+		lastUsed:  time.Now(),
+	}, nil
+}
+
+func (w *piperWorker) isClosed() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.closed
+}
+
+// synthesize sends one line of text to the warm process and waits for the
+// WAV file it produces in outputDir, identifying it by diffing the
+// directory's contents before and after rather than assuming a particular
+// filename convention.
+func (w *piperWorker) synthesize(ctx context.Context, text string) ([]byte, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil, fmt.Errorf("piper worker is closed")
+	}
+	w.lastUsed = time.Now()
+
+	before, err := listDir(w.outputDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worker output dir: %w", err)
+	}
+
+	line := strings.ReplaceAll(text, "\n", " ") + "\n"
+	if _, err := w.stdin.WriteString(line); err != nil {
+		return nil, fmt.Errorf("failed to write to piper worker: %w", err)
+	}
+
+	deadline := time.Now().Add(piperWorkerWriteWait)
+	for time.Now().Before(deadline) {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		after, err := listDir(w.outputDir)
+		if err == nil {
+			for name := range after {
+				if !before[name] {
+					return readSettledFile(filepath.Join(w.outputDir, name))
+				}
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	return nil, fmt.Errorf("timed out waiting for piper worker output")
+}
+
+func (w *piperWorker) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return
+	}
+	w.closed = true
+	w.stdin.Close()
+	if w.cmd.Process != nil {
+		w.cmd.Process.Kill()
+	}
+	w.cmd.Wait()
+	os.RemoveAll(w.outputDir)
+}
+
+func listDir(dir string) (map[string]bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		names[e.Name()] = true
+	}
+	return names, nil
+}
+
+// readSettledFile waits for path's size to stop changing (piper is still
+// writing to it) before reading it back, since the file appears in the
+// directory listing as soon as it's created, not once it's fully written.
+func readSettledFile(path string) ([]byte, error) {
+	var lastSize int64 = -1
+	for i := 0; i < 100; i++ {
+		fi, err := os.Stat(path)
+		if err != nil {
+			time.Sleep(10 * time.Millisecond)
+			continue
+		}
+		if fi.Size() == lastSize && fi.Size() > 0 {
+			break
+		}
+		lastSize = fi.Size()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read piper worker output: %w", err)
+	}
+	os.Remove(path)
+	return data, nil
+}
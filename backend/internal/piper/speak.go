@@ -0,0 +1,77 @@
+package piper
+
+import (
+	"context"
+	"fmt"
+
+	"alice-backend/internal/piper/playback"
+)
+
+// Speak synthesizes text sentence-by-sentence and plays each sentence
+// directly through the OS audio sink as soon as it's ready, instead of
+// returning audio bytes for the caller to hand to an HTTP response and a
+// browser <audio> element. For a desktop assistant that round trip adds
+// hundreds of milliseconds and makes barge-in awkward; playing directly
+// gets the first sentence onto the speaker while later sentences are still
+// being synthesized.
+//
+// Speak requires a binary built with -tags piper_playback (see the
+// playback package); without it, every call fails with
+// playback.ErrNotCompiled and callers should fall back to Synthesize.
+//
+// Cancelling ctx stops playback before the next sentence starts and
+// discards whatever has already been buffered in the sink rather than
+// letting it play out, so an in-progress utterance can be interrupted
+// (barge-in) instead of only ever finishing or failing outright.
+func (s *TTSService) Speak(ctx context.Context, text, voice string) error {
+	if !s.IsReady() {
+		return fmt.Errorf("TTS service is not ready")
+	}
+	if text == "" {
+		return fmt.Errorf("text cannot be empty")
+	}
+
+	sentences := splitSentences(text)
+	if len(sentences) == 0 {
+		return fmt.Errorf("no synthesizable text")
+	}
+
+	sink, err := playback.NewSink()
+	if err != nil {
+		return fmt.Errorf("direct playback unavailable: %w", err)
+	}
+	defer sink.Close()
+
+	var opened bool
+	for _, sentence := range sentences {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		wav, err := s.synthesize(ctx, sentence, voice, s.config.Speed)
+		if err != nil {
+			return fmt.Errorf("failed to synthesize %q: %w", sentence, err)
+		}
+
+		samples, sampleRate, channels, err := parseWAV(wav)
+		if err != nil {
+			return fmt.Errorf("failed to decode synthesized audio: %w", err)
+		}
+
+		if !opened {
+			if err := sink.Open(sampleRate, channels); err != nil {
+				return fmt.Errorf("failed to open audio sink: %w", err)
+			}
+			opened = true
+		}
+
+		if err := sink.Write(samples); err != nil {
+			return fmt.Errorf("failed to write to audio sink: %w", err)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return sink.Drain()
+}
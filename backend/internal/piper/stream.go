@@ -0,0 +1,143 @@
+package piper
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"alice-backend/internal/audio/codec"
+)
+
+// AudioChunk is one increment of a streaming synthesis: either an audio
+// payload (in the requested format) for a completed sentence, or a terminal Err if synthesis failed
+// partway through. The channel is closed after an Err chunk or once all
+// sentences have been emitted.
+type AudioChunk struct {
+	Data []byte
+	Err  error
+}
+
+// SynthesizeStream splits text into sentences and synthesizes them one at
+// a time, emitting each as it finishes instead of blocking until the
+// entire input has been spoken. This cuts time-to-first-audio for long
+// assistant responses down from "however long the whole paragraph takes"
+// to "however long the first sentence takes".
+//
+// For format codec.FormatWAV, every chunk after the first has its WAV
+// header stripped so the stream can be treated as one continuous audio/wav
+// body by the caller. Other formats (opus/mp3/flac) are each independently
+// encoded containers, so they can't be concatenated the same way: every
+// chunk is a complete, self-contained file the caller must decode on its
+// own. That still gets the latency win; it just isn't one seamless stream.
+func (s *TTSService) SynthesizeStream(ctx context.Context, text, voice string, format codec.Format) (<-chan AudioChunk, error) {
+	if !s.IsReady() {
+		return nil, fmt.Errorf("TTS service is not ready")
+	}
+	if text == "" {
+		return nil, fmt.Errorf("text cannot be empty")
+	}
+
+	sentences := splitSentences(text)
+	if len(sentences) == 0 {
+		return nil, fmt.Errorf("no synthesizable text")
+	}
+
+	out := make(chan AudioChunk)
+	go s.runSynthesizeStream(ctx, sentences, voice, format, out)
+	return out, nil
+}
+
+func (s *TTSService) runSynthesizeStream(ctx context.Context, sentences []string, voice string, format codec.Format, out chan<- AudioChunk) {
+	defer close(out)
+
+	for i, sentence := range sentences {
+		if ctx.Err() != nil {
+			return
+		}
+
+		audio, err := s.Synthesize(ctx, sentence, voice, format)
+		if err != nil {
+			select {
+			case out <- AudioChunk{Err: err}:
+			case <-ctx.Done():
+			}
+			return
+		}
+
+		if i > 0 && (format == "" || format == codec.FormatWAV) {
+			audio = stripWAVHeader(audio)
+		}
+
+		select {
+		case out <- AudioChunk{Data: audio}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// splitSentences breaks text on sentence-ending punctuation (., !, ?)
+// followed by whitespace, so each sentence can be handed to Piper as its
+// own synthesis call. Each sentence is trimmed of surrounding whitespace
+// and empty sentences are dropped.
+func splitSentences(text string) []string {
+	var sentences []string
+	var b strings.Builder
+
+	flush := func() {
+		s := strings.TrimSpace(b.String())
+		if s != "" {
+			sentences = append(sentences, s)
+		}
+		b.Reset()
+	}
+
+	runes := []rune(text)
+	for i, r := range runes {
+		b.WriteRune(r)
+		if r != '.' && r != '!' && r != '?' {
+			continue
+		}
+		// Only split here if this looks like the end of a sentence: either
+		// we've hit the end of input, or the punctuation is followed by
+		// whitespace (avoids splitting on things like "3.14").
+		if i+1 >= len(runes) || runes[i+1] == ' ' || runes[i+1] == '\n' || runes[i+1] == '\t' {
+			flush()
+		}
+	}
+	flush()
+
+	return sentences
+}
+
+// stripWAVHeader returns just the PCM payload of a canonical RIFF/WAVE
+// file, locating the "data" subchunk the same way whisper's wavDecoder
+// does. If data can't be found (malformed input), the original bytes are
+// returned unchanged rather than dropping audio.
+func stripWAVHeader(wav []byte) []byte {
+	if len(wav) < 12 || string(wav[0:4]) != "RIFF" || string(wav[8:12]) != "WAVE" {
+		return wav
+	}
+
+	pos := 12
+	for pos+8 <= len(wav) {
+		chunkID := string(wav[pos : pos+4])
+		chunkSize := int(binary.LittleEndian.Uint32(wav[pos+4 : pos+8]))
+		chunkStart := pos + 8
+		if chunkStart+chunkSize > len(wav) {
+			break
+		}
+
+		if chunkID == "data" {
+			return wav[chunkStart : chunkStart+chunkSize]
+		}
+
+		pos = chunkStart + chunkSize
+		if chunkSize%2 == 1 {
+			pos++ // chunks are word-aligned
+		}
+	}
+
+	return wav
+}
@@ -1,10 +1,8 @@
 package piper
 
 import (
-	"archive/tar"
-	"archive/zip"
-	"compress/gzip"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
@@ -14,10 +12,13 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"alice-backend/internal/audio/codec"
+	"alice-backend/internal/downloader"
 	"alice-backend/internal/embedded"
 )
 
@@ -30,6 +31,11 @@ type TTSService struct {
 	info         *ServiceInfo
 	defaultVoice string
 	assetManager *embedded.AssetManager
+	downloads    *downloader.Manager
+	catalog      *VoiceCatalog
+	onnxEngine   *onnxEngine // non-nil once Config.Backend == "onnx" has been loaded
+	pool         *piperWorkerPool
+	backend      Backend // non-nil once Config.Provider selects an alternate (non-Piper) Backend
 }
 
 // Config holds TTS configuration
@@ -38,16 +44,58 @@ type Config struct {
 	ModelPath string
 	Voice     string
 	Speed     float32
+
+	// Backend selects the synthesis engine: "binary" (default) shells out
+	// to the piper executable per call, "onnx" phonemizes text and runs
+	// the voice's VITS model in-process through ONNX Runtime, loading and
+	// caching each voice's session once instead of spawning a process per
+	// utterance. The onnx backend downloads its own ONNX Runtime shared
+	// library on demand, but phonemization needs espeak-ng headers present
+	// at build time, so it only works in binaries built with -tags
+	// piper_phonemize; without that tag, synthesis falls back to
+	// placeholder audio the same way it does when the piper binary is
+	// missing.
+	Backend string
+
+	// Provider selects which Backend synthesizes speech: "" or "piper"
+	// (default) uses TTSService's own Piper machinery above; "coqui",
+	// "openai", and "google" route through the matching Backend in
+	// backend_*.go instead, configured by the matching field below.
+	Provider    string
+	Coqui       CoquiConfig
+	OpenAI      OpenAIConfig
+	GoogleCloud GoogleCloudConfig
+
+	// AssetMirrorURL, if set, is passed to the TTSService's AssetManager as
+	// an HTTP fallback for piper archives missing from both the embedded
+	// assets and the local cache. See embedded.AssetManager.SetAssetMirror.
+	AssetMirrorURL string
+
+	// ProgressReporter, if set, is passed to the TTSService's AssetManager
+	// so download/extract/verify progress during Initialize's EnsureAssets
+	// call is reported to it. See embedded.AssetManager.SetProgressReporter.
+	ProgressReporter embedded.ProgressReporter
 }
 
 // Voice represents a TTS voice
 type Voice struct {
 	Name        string `json:"name"`
 	Language    string `json:"language"`
+	Speaker     string `json:"speaker,omitempty"`
 	Gender      string `json:"gender"`
 	Quality     string `json:"quality"`
 	SampleRate  int    `json:"sample_rate"`
 	Description string `json:"description"`
+
+	// MD5, SHA256, Size, and DownloadURL are populated from the voice
+	// catalog's manifest (see catalog.go) once a voice is resolved from it.
+	// The upstream rhasspy/piper-voices manifest only publishes an MD5
+	// digest per file, so SHA256 is left empty rather than fabricated; the
+	// download job verifies against MD5 instead.
+	MD5         string `json:"md5,omitempty"`
+	SHA256      string `json:"sha256,omitempty"`
+	Size        int64  `json:"size,omitempty"`
+	DownloadURL string `json:"download_url,omitempty"`
 }
 
 // ServiceInfo contains information about the TTS service
@@ -65,12 +113,17 @@ type ServiceInfo struct {
 func NewTTSService(config *Config) *TTSService {
 	// Create asset manager with current working directory as base
 	assetManager := embedded.NewAssetManager(".")
-	
+	assetManager.SetAssetMirror(config.AssetMirrorURL)
+	assetManager.SetProgressReporter(config.ProgressReporter)
+
 	return &TTSService{
 		config:       config,
 		voices:       make(map[string]*Voice),
 		defaultVoice: "en_US-amy-medium", // Set default to young woman voice
 		assetManager: assetManager,
+		downloads:    downloader.NewManager(),
+		catalog:      NewVoiceCatalog(),
+		pool:         newPiperWorkerPool(),
 		info: &ServiceInfo{
 			Name:        "Piper TTS",
 			Version:     "1.0.0",
@@ -90,6 +143,32 @@ func (s *TTSService) Initialize(ctx context.Context) error {
 
 	log.Println("Initializing Piper TTS service...")
 
+	backend, err := newBackend(s.config.Provider, s.config)
+	if err != nil {
+		return err
+	}
+	s.backend = backend
+	if s.backend != nil {
+		if err := s.backend.Warmup(ctx); err != nil {
+			log.Printf("Warning: %s backend warmup failed: %v", s.config.Provider, err)
+		}
+		s.info.Metadata["backend"] = s.config.Provider
+		s.loadVoicesFromBackend(ctx)
+
+		formats := make([]string, 0, len(codec.Available()))
+		for _, f := range codec.Available() {
+			formats = append(formats, string(f))
+		}
+		s.info.Metadata["audio_formats"] = strings.Join(formats, ",")
+
+		s.ready = true
+		s.info.Status = "ready"
+		s.info.LastUpdated = time.Now()
+
+		log.Println("Piper TTS service initialized successfully")
+		return nil
+	}
+
 	// First, try to extract embedded assets
 	if err := s.assetManager.EnsureAssets(ctx); err != nil {
 		log.Printf("Warning: Failed to extract embedded assets: %v", err)
@@ -101,14 +180,34 @@ func (s *TTSService) Initialize(ctx context.Context) error {
 		s.config.ModelPath = s.assetManager.GetModelPath("piper")
 	}
 
-	// Ensure Piper binary exists (don't fail initialization if missing)
-	if err := s.ensurePiper(ctx); err != nil {
-		log.Printf("Warning: %v - TTS will use fallback audio", err)
-		// Continue initialization with placeholder functionality
+	if s.config.Backend == "" {
+		s.config.Backend = "binary"
+	}
+
+	if s.config.Backend == "onnx" {
+		engine, err := newOnnxEngine(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to initialize onnx piper engine: %w", err)
+		}
+		s.onnxEngine = engine
+		s.info.Metadata["backend"] = "onnx"
+	} else {
+		// Ensure Piper binary exists (don't fail initialization if missing)
+		if err := s.ensurePiper(ctx); err != nil {
+			log.Printf("Warning: %v - TTS will use fallback audio", err)
+			// Continue initialization with placeholder functionality
+		}
+		s.info.Metadata["backend"] = "binary"
 	}
 
 	// Load available voices
-	s.loadVoices()
+	s.loadVoices(ctx)
+
+	formats := make([]string, 0, len(codec.Available()))
+	for _, f := range codec.Available() {
+		formats = append(formats, string(f))
+	}
+	s.info.Metadata["audio_formats"] = strings.Join(formats, ",")
 
 	s.ready = true
 	s.info.Status = "ready"
@@ -118,21 +217,59 @@ func (s *TTSService) Initialize(ctx context.Context) error {
 	return nil
 }
 
-// loadVoices loads available TTS voices
-func (s *TTSService) loadVoices() {
-	// Real Piper voices that can be downloaded
-	voices := []*Voice{
+// loadVoices populates s.voices from the upstream voice catalog, falling
+// back to a small built-in set of known-good English voices if the manifest
+// can't be fetched (e.g. no network access yet at startup).
+func (s *TTSService) loadVoices(ctx context.Context) {
+	voices, err := s.catalog.List(ctx, "")
+	if err != nil || len(voices) == 0 {
+		log.Printf("Voice catalog unavailable (%v), falling back to built-in voice list", err)
+		voices = fallbackVoices()
+	}
+
+	s.voices = make(map[string]*Voice, len(voices))
+	s.info.Voices = voices
+
+	for _, voice := range voices {
+		s.voices[voice.Name] = voice
+	}
+}
+
+// loadVoicesFromBackend populates s.voices from an alternate Backend's
+// ListVoices instead of the Piper voice catalog, mirroring loadVoices so
+// GetVoices/GetInfo behave the same regardless of which backend is active.
+func (s *TTSService) loadVoicesFromBackend(ctx context.Context) {
+	voices, err := s.backend.ListVoices(ctx)
+	if err != nil {
+		log.Printf("Failed to list %s backend voices: %v", s.config.Provider, err)
+		voices = nil
+	}
+
+	s.voices = make(map[string]*Voice, len(voices))
+	s.info.Voices = voices
+	for _, voice := range voices {
+		s.voices[voice.Name] = voice
+	}
+}
+
+// fallbackVoices is the small set of English voices TTSService knows about
+// without needing to reach the voice catalog, used when the manifest fetch
+// fails.
+func fallbackVoices() []*Voice {
+	return []*Voice{
 		{
 			Name:        "en_US-amy-medium",
 			Language:    "en-US",
+			Speaker:     "amy",
 			Gender:      "female",
 			Quality:     "medium",
 			SampleRate:  22050,
 			Description: "Amy - English US female voice (Piper)",
 		},
 		{
-			Name:        "en_US-hfc_female-medium", 
+			Name:        "en_US-hfc_female-medium",
 			Language:    "en-US",
+			Speaker:     "hfc_female",
 			Gender:      "female",
 			Quality:     "medium",
 			SampleRate:  22050,
@@ -140,20 +277,14 @@ func (s *TTSService) loadVoices() {
 		},
 		{
 			Name:        "en_US-kristin-medium",
-			Language:    "en-US", 
+			Language:    "en-US",
+			Speaker:     "kristin",
 			Gender:      "female",
 			Quality:     "medium",
 			SampleRate:  22050,
 			Description: "Kristin - English US female voice (Piper)",
 		},
 	}
-
-	s.voices = make(map[string]*Voice)
-	s.info.Voices = voices
-
-	for _, voice := range voices {
-		s.voices[voice.Name] = voice
-	}
 }
 
 // IsReady returns true if the service is ready
@@ -163,11 +294,21 @@ func (s *TTSService) IsReady() bool {
 	return s.ready
 }
 
-// GetVoices returns available voices
+// GetVoices returns the voices actually installed on disk (see
+// ListInstalledVoices), or, when an alternate Backend is active, every
+// voice it reports - those aren't file-based installs, so "installed"
+// doesn't apply to them.
 func (s *TTSService) GetVoices() []*Voice {
 	s.mu.RLock()
-	defer s.mu.RUnlock()
+	backend := s.backend
+	s.mu.RUnlock()
+
+	if backend == nil {
+		return s.ListInstalledVoices()
+	}
 
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	voices := make([]*Voice, 0, len(s.voices))
 	for _, voice := range s.voices {
 		voices = append(voices, voice)
@@ -175,6 +316,93 @@ func (s *TTSService) GetVoices() []*Voice {
 	return voices
 }
 
+// ListInstalledVoices scans modelDir for installed Piper voice models
+// (a name.onnx + name.onnx.json pair) and returns a Voice per one found,
+// preferring the voice catalog's metadata for names it recognizes and
+// falling back to parsing the .onnx.json sidecar directly otherwise (e.g.
+// offline, or a model installed by hand).
+func (s *TTSService) ListInstalledVoices() []*Voice {
+	modelDir := s.config.ModelPath
+	if modelDir == "" {
+		modelDir = "models/piper"
+	}
+
+	entries, err := os.ReadDir(modelDir)
+	if err != nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	voices := make([]*Voice, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".onnx") {
+			continue
+		}
+		voiceName := strings.TrimSuffix(name, ".onnx")
+
+		if voice, ok := s.voices[voiceName]; ok {
+			voices = append(voices, voice)
+			continue
+		}
+		voices = append(voices, voiceFromModelFile(modelDir, voiceName))
+	}
+
+	sort.Slice(voices, func(i, j int) bool { return voices[i].Name < voices[j].Name })
+	return voices
+}
+
+// voiceFromModelFile builds a Voice for an installed model the catalog
+// doesn't recognize, deriving language/speaker/quality from the
+// "{lang}-{speaker}-{quality}" naming convention every Piper voice uses and
+// reading its sample rate from the .onnx.json sidecar, if present.
+func voiceFromModelFile(modelDir, name string) *Voice {
+	v := &Voice{Name: name, SampleRate: 22050}
+
+	if parts := strings.SplitN(name, "-", 3); len(parts) == 3 {
+		v.Language = parts[0]
+		v.Speaker = parts[1]
+		v.Quality = parts[2]
+	}
+
+	var cfg onnxVoiceConfig
+	if data, err := os.ReadFile(filepath.Join(modelDir, name+".onnx.json")); err == nil {
+		if err := json.Unmarshal(data, &cfg); err == nil && cfg.Audio.SampleRate > 0 {
+			v.SampleRate = cfg.Audio.SampleRate
+		}
+	}
+
+	return v
+}
+
+// RemoveVoice deletes an installed voice's model and sidecar config from
+// disk so a user can free up space. It leaves the in-memory catalog and
+// default voice selection untouched, so synthesizing with a removed voice
+// still finds it in the catalog and ensureVoiceModel downloads it again
+// (see synthesizeInternal).
+func (s *TTSService) RemoveVoice(name string) error {
+	modelDir := s.config.ModelPath
+	if modelDir == "" {
+		modelDir = "models/piper"
+	}
+
+	modelFile := filepath.Join(modelDir, name+".onnx")
+	if _, err := os.Stat(modelFile); err != nil {
+		return fmt.Errorf("voice %s is not installed", name)
+	}
+	if err := os.Remove(modelFile); err != nil {
+		return fmt.Errorf("failed to remove voice model: %w", err)
+	}
+
+	configFile := filepath.Join(modelDir, name+".onnx.json")
+	if err := os.Remove(configFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove voice config: %w", err)
+	}
+	return nil
+}
+
 // GetInfo returns service information
 func (s *TTSService) GetInfo() *ServiceInfo {
 	s.mu.RLock()
@@ -186,8 +414,45 @@ func (s *TTSService) GetInfo() *ServiceInfo {
 	return &info
 }
 
-// Synthesize converts text to speech using Piper
-func (s *TTSService) Synthesize(ctx context.Context, text string, voice string) ([]byte, error) {
+// Synthesize converts text to speech using Piper, encoding the result into
+// the requested output format. Piper itself only ever produces WAV; format
+// conversion (if format isn't codec.FormatWAV) happens as a final step via
+// the internal/audio/codec package.
+func (s *TTSService) Synthesize(ctx context.Context, text string, voice string, format codec.Format) ([]byte, error) {
+	return s.synthesizeInternal(ctx, text, voice, s.config.Speed, format)
+}
+
+// SynthesizeOptions customizes a single SynthesizeToWriter call without
+// touching the service-wide Config, so each caller (e.g. an individual HTTP
+// request) can pick its own output format and speed independently of the
+// configured defaults.
+type SynthesizeOptions struct {
+	Format codec.Format
+	Speed  float32 // overrides Config.Speed for this call only; 0 keeps the configured default
+}
+
+// SynthesizeToWriter is the writer-output counterpart to Synthesize: it
+// writes the encoded audio to w instead of returning it as a []byte, so an
+// HTTP handler can pass its response body directly (see the
+// OpenAI-compatible /v1/audio/speech endpoint). The whole utterance is still
+// synthesized and encoded in memory first, then written to w in one call.
+func (s *TTSService) SynthesizeToWriter(ctx context.Context, text, voice string, opts SynthesizeOptions, w io.Writer) error {
+	speed := opts.Speed
+	if speed == 0 {
+		speed = s.config.Speed
+	}
+
+	data, err := s.synthesizeInternal(ctx, text, voice, speed, opts.Format)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// synthesizeInternal backs both Synthesize and SynthesizeToWriter, taking an
+// explicit speed so each caller can override Config.Speed per call.
+func (s *TTSService) synthesizeInternal(ctx context.Context, text string, voice string, speed float32, format codec.Format) ([]byte, error) {
 	if !s.IsReady() {
 		return nil, fmt.Errorf("TTS service is not ready")
 	}
@@ -196,6 +461,10 @@ func (s *TTSService) Synthesize(ctx context.Context, text string, voice string)
 		return nil, fmt.Errorf("text cannot be empty")
 	}
 
+	if s.backend != nil {
+		return s.synthesizeWithBackend(ctx, text, voice, speed, format)
+	}
+
 	if voice == "" {
 		voice = s.config.Voice
 		if voice == "" {
@@ -205,11 +474,11 @@ func (s *TTSService) Synthesize(ctx context.Context, text string, voice string)
 
 	s.mu.RLock()
 	selectedVoice, exists := s.voices[voice]
-	
+
 	// If requested voice not found, use default young woman voice (Amy)
 	if !exists {
 		log.Printf("Voice '%s' not found, trying default voices...", voice)
-		
+
 		// First try to use configured default voice
 		if fallbackVoice, exists := s.voices[s.defaultVoice]; exists {
 			selectedVoice = fallbackVoice
@@ -235,21 +504,61 @@ func (s *TTSService) Synthesize(ctx context.Context, text string, voice string)
 	}
 
 	// Ensure voice model exists
+	var wav []byte
 	if err := s.ensureVoiceModel(ctx, voice); err != nil {
 		log.Printf("Failed to ensure voice model %s: %v", voice, err)
 		// Fall back to placeholder for now
-		return s.generatePlaceholderWAV(text, selectedVoice), nil
+		wav = s.generatePlaceholderWAV(text, selectedVoice)
+	} else if audioData, err := s.synthesize(ctx, text, voice, speed); err != nil {
+		log.Printf("Failed to synthesize with Piper: %v", err)
+		// Fall back to placeholder
+		wav = s.generatePlaceholderWAV(text, selectedVoice)
+	} else {
+		wav = audioData
 	}
 
-	// Use Piper to synthesize speech
-	audioData, err := s.synthesizeWithPiper(ctx, text, voice)
+	if format == "" || format == codec.FormatWAV {
+		return wav, nil
+	}
+	return encodeFormat(wav, format)
+}
+
+// synthesizeWithBackend is synthesizeInternal's path for an alternate
+// (non-Piper) Backend: voice resolution and on-disk model management are
+// the backend's own problem, so this skips straight to Synthesize and falls
+// back to placeholder audio on failure, same as the Piper path does.
+func (s *TTSService) synthesizeWithBackend(ctx context.Context, text, voice string, speed float32, format codec.Format) ([]byte, error) {
+	if voice == "" {
+		s.mu.RLock()
+		voice = s.defaultVoice
+		s.mu.RUnlock()
+	}
+
+	wav, err := s.backend.Synthesize(ctx, text, voice, speed)
 	if err != nil {
-		log.Printf("Failed to synthesize with Piper: %v", err)
-		// Fall back to placeholder
-		return s.generatePlaceholderWAV(text, selectedVoice), nil
+		log.Printf("Failed to synthesize with %s backend: %v", s.config.Provider, err)
+		wav = s.generatePlaceholderWAV(text, &Voice{Name: voice})
 	}
 
-	return audioData, nil
+	if format == "" || format == codec.FormatWAV {
+		return wav, nil
+	}
+	return encodeFormat(wav, format)
+}
+
+// encodeFormat re-encodes a synthesized WAV payload into format using the
+// matching internal/audio/codec.Encoder.
+func encodeFormat(wav []byte, format codec.Format) ([]byte, error) {
+	samples, sampleRate, channels, err := parseWAV(wav)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode synthesized audio: %w", err)
+	}
+
+	enc, err := codec.Get(format)
+	if err != nil {
+		return nil, err
+	}
+	return enc.Encode(samples, sampleRate, channels)
 }
 
 // generatePlaceholderWAV generates a speech-like WAV file with tones based on text
@@ -515,7 +824,7 @@ func (s *TTSService) ensurePiper(ctx context.Context) error {
 	log.Printf("Attempting to download Piper binary automatically...")
 	
 	// Try to download automatically
-	if err := s.downloadPiperBinary(); err != nil {
+	if err := s.downloadPiperBinary(ctx); err != nil {
 		log.Printf("Failed to download Piper binary: %v", err)
 		log.Printf("Please download Piper manually from: https://github.com/rhasspy/piper/releases")
 		log.Printf("Extract the binary to: %s", s.config.PiperPath)
@@ -559,28 +868,115 @@ func (s *TTSService) ensureVoiceModel(ctx context.Context, voice string) error {
 	}
 
 	log.Printf("Voice model %s not found, attempting to download...", voice)
-	
-	// Try to download the voice model automatically
-	if err := s.downloadVoiceModel(voice, modelDir); err != nil {
-		log.Printf("Failed to download voice model: %v", err)
+
+	job, err := s.DownloadVoice(ctx, voice)
+	if err != nil {
+		log.Printf("Failed to start voice model download: %v", err)
 		log.Printf("Please download manually from: https://huggingface.co/rhasspy/piper-voices/tree/main")
 		log.Printf("Place files at: %s and %s", modelFile, configFile)
-		return fmt.Errorf("voice model not found - please download manually")
+		return fmt.Errorf("voice model not found - please download manually: %w", err)
+	}
+
+	if err := s.waitForDownload(ctx, job); err != nil {
+		return err
 	}
-	
 	log.Printf("Voice model %s downloaded successfully", voice)
 	return nil
 }
 
-// synthesizeWithPiper uses the Piper binary to synthesize speech
-func (s *TTSService) synthesizeWithPiper(ctx context.Context, text, voice string) ([]byte, error) {
+// waitForDownload blocks until job finishes, so callers that just want
+// "the file is ready" don't need to poll VoiceDownloadStatus themselves.
+func (s *TTSService) waitForDownload(ctx context.Context, job *downloader.Job) error {
+	for {
+		switch job.Status() {
+		case downloader.JobDone:
+			return nil
+		case downloader.JobFailed:
+			return fmt.Errorf("download failed: %w", job.Err())
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+// synthesize runs text through whichever backend Config.Backend selected:
+// the in-process onnx engine if one was loaded during Initialize, otherwise
+// the piper binary. speed overrides Config.Speed for this call; 0 keeps the
+// configured default.
+func (s *TTSService) synthesize(ctx context.Context, text, voice string, speed float32) ([]byte, error) {
+	s.mu.RLock()
+	engine := s.onnxEngine
+	s.mu.RUnlock()
+
+	if engine != nil {
+		return s.synthesizeWithOnnx(engine, text, voice, speed)
+	}
+	return s.synthesizeWithPiper(ctx, text, voice, speed)
+}
+
+// synthesizeWithOnnx runs the voice's VITS model in-process through ONNX
+// Runtime and wraps the resulting PCM in a WAV container via the same
+// codec.Encoder the rest of the package uses for format conversion.
+func (s *TTSService) synthesizeWithOnnx(engine *onnxEngine, text, voice string, speed float32) ([]byte, error) {
+	modelDir := "models/piper"
+	if s.config.ModelPath != "" {
+		modelDir = s.config.ModelPath
+	}
+	modelFile := filepath.Join(modelDir, voice+".onnx")
+
+	samples, sampleRate, err := engine.synthesize(voice, modelFile, text, speed)
+	if err != nil {
+		return nil, fmt.Errorf("onnx synthesis failed: %w", err)
+	}
+
+	enc, err := codec.Get(codec.FormatWAV)
+	if err != nil {
+		return nil, err
+	}
+	return enc.Encode(samples, sampleRate, 1)
+}
+
+// synthesizeWithPiper uses the Piper binary to synthesize speech. When speed
+// matches the service-wide default, this reuses a warm per-voice piper
+// process from s.pool instead of paying process-startup cost on every call;
+// a per-request speed override can't be honored by an already-running
+// worker (piper's --length_scale is fixed at startup), so that case falls
+// back to spawning a dedicated one-shot process.
+func (s *TTSService) synthesizeWithPiper(ctx context.Context, text, voice string, speed float32) ([]byte, error) {
 	modelDir := "models/piper"
 	if s.config.ModelPath != "" {
 		modelDir = s.config.ModelPath
 	}
 
 	modelFile := filepath.Join(modelDir, voice+".onnx")
+	espeakDataPath := filepath.Join(filepath.Dir(s.config.PiperPath), "espeak-ng-data")
 
+	if speed == s.config.Speed {
+		worker, err := s.pool.get(ctx, s.config.PiperPath, espeakDataPath, modelFile, voice, speed)
+		if err == nil {
+			audioData, err := worker.synthesize(ctx, text)
+			if err == nil {
+				log.Printf("Piper synthesis complete (warm worker): %d bytes", len(audioData))
+				return audioData, nil
+			}
+			log.Printf("Warm piper worker failed, falling back to one-shot process: %v", err)
+		} else {
+			log.Printf("Failed to get warm piper worker, falling back to one-shot process: %v", err)
+		}
+	}
+
+	return s.synthesizeWithPiperOneShot(ctx, text, modelFile, speed, espeakDataPath)
+}
+
+// synthesizeWithPiperOneShot spawns a dedicated piper process for a single
+// utterance, the way synthesizeWithPiper always used to before warm workers
+// were introduced. It's still needed for speed overrides a warm worker can't
+// honor, and as a fallback if the worker pool is unavailable.
+func (s *TTSService) synthesizeWithPiperOneShot(ctx context.Context, text, modelFile string, speed float32, espeakDataPath string) ([]byte, error) {
 	// Create temporary files for input and output
 	tmpDir := os.TempDir()
 	inputFile := filepath.Join(tmpDir, fmt.Sprintf("piper_input_%d.txt", time.Now().UnixNano()))
@@ -601,16 +997,15 @@ func (s *TTSService) synthesizeWithPiper(ctx context.Context, text, voice string
 	}
 
 	// Add speed if specified
-	if s.config.Speed > 0 && s.config.Speed != 1.0 {
-		args = append(args, "--length_scale", fmt.Sprintf("%.2f", 1.0/s.config.Speed))
+	if speed > 0 && speed != 1.0 {
+		args = append(args, "--length_scale", fmt.Sprintf("%.2f", 1.0/speed))
 	}
 
 	// Run Piper
 	cmd := exec.CommandContext(ctx, s.config.PiperPath, args...)
 	cmd.Stdin = strings.NewReader(text)
-	
+
 	// Set espeak-ng data path environment variable
-	espeakDataPath := filepath.Join(filepath.Dir(s.config.PiperPath), "espeak-ng-data")
 	cmd.Env = append(os.Environ(), "ESPEAK_DATA_PATH="+espeakDataPath)
 
 	_, err := cmd.CombinedOutput()
@@ -629,7 +1024,7 @@ func (s *TTSService) synthesizeWithPiper(ctx context.Context, text, voice string
 }
 
 // downloadPiperBinary downloads the appropriate Piper binary for the current platform
-func (s *TTSService) downloadPiperBinary() error {
+func (s *TTSService) downloadPiperBinary(ctx context.Context) error {
 	var downloadURL, fileName string
 	
 	// Determine platform and download URL
@@ -662,9 +1057,17 @@ func (s *TTSService) downloadPiperBinary() error {
 
 	log.Printf("Downloading Piper binary from: %s", downloadURL)
 	archivePath := filepath.Join("bin", fileName)
-	
-	// Download the archive
-	if err := s.downloadFile(downloadURL, archivePath); err != nil {
+
+	// Route through the shared downloader.Manager instead of the old
+	// one-shot downloadFile, so a flaky connection resumes via HTTP Range
+	// instead of restarting from byte 0. Piper's GitHub release assets
+	// don't publish a checksums file, so there's no trusted SHA256 to check
+	// here (unlike voice models, which are verified against the catalog's
+	// published MD5 in DownloadVoice) - this closes the resume gap
+	// honestly, without fabricating a hash that would just make every
+	// download fail.
+	job := s.downloads.Start("piper-binary:"+fileName, downloadURL, archivePath, "")
+	if err := s.waitForDownload(ctx, job); err != nil {
 		return fmt.Errorf("failed to download archive: %w", err)
 	}
 
@@ -720,111 +1123,27 @@ func (s *TTSService) downloadFile(url, filepath string) error {
 	return nil
 }
 
-// extractPiperBinary extracts the Piper binary from the downloaded archive
-func (s *TTSService) extractPiperBinary(archivePath string) error {
-	if strings.HasSuffix(archivePath, ".zip") {
-		return s.extractZip(archivePath)
-	} else if strings.HasSuffix(archivePath, ".tar.gz") {
-		return s.extractTarGz(archivePath)
-	}
-	return fmt.Errorf("unsupported archive format: %s", archivePath)
-}
+// requiredPiperDLLs are the Windows dependencies shipped alongside piper.exe
+// in the official release zip; without them the binary fails to start.
+var requiredPiperDLLs = []string{"espeak-ng.dll", "onnxruntime_providers_shared.dll", "onnxruntime.dll", "piper_phonemize.dll"}
 
-// extractZip extracts piper.exe from a ZIP archive
-func (s *TTSService) extractZip(archivePath string) error {
-	reader, err := zip.OpenReader(archivePath)
+// extractPiperBinary extracts the Piper binary (and, on Windows, its DLL and
+// espeak-ng-data dependencies) from the downloaded archive, via the
+// zip-slip-safe, size-capped ArchiveExtractor rather than trusting
+// archive-supplied paths directly.
+func (s *TTSService) extractPiperBinary(archivePath string) error {
+	extractor, err := openArchiveExtractor(archivePath)
 	if err != nil {
 		return err
 	}
-	defer reader.Close()
-
+	defer extractor.Close()
 
-	// First pass: extract required DLLs and espeak-ng-data
-	requiredDLLs := []string{"espeak-ng.dll", "onnxruntime_providers_shared.dll", "onnxruntime.dll", "piper_phonemize.dll"}
-	extractedFiles := 0
 	binDir := filepath.Dir(s.config.PiperPath)
-	
-	for _, file := range reader.File {
-		fileName := strings.ToLower(filepath.Base(file.Name))
-		
-		// Check if this is a required DLL
-		if !file.FileInfo().IsDir() {
-			for _, dll := range requiredDLLs {
-				if fileName == dll {
-					dllPath := filepath.Join(binDir, dll)
-					if err := s.extractSingleFileFromZip(file, dllPath); err != nil {
-						log.Printf("Warning: Failed to extract %s: %v", dll, err)
-					} else {
-						log.Printf("Extracted required DLL: %s", dllPath)
-						extractedFiles++
-					}
-					break
-				}
-			}
-		}
-		
-		// Extract espeak-ng-data directory
-		if strings.HasPrefix(file.Name, "piper/espeak-ng-data/") {
-			// Remove "piper/" prefix to get relative path from bin directory
-			relativePath := strings.TrimPrefix(file.Name, "piper/")
-			targetPath := filepath.Join(binDir, relativePath)
-			
-			if file.FileInfo().IsDir() {
-				// Create directory
-				if err := os.MkdirAll(targetPath, 0755); err != nil {
-					log.Printf("Warning: Failed to create directory %s: %v", targetPath, err)
-				}
-			} else {
-				// Extract file
-				if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
-					log.Printf("Warning: Failed to create directory %s: %v", filepath.Dir(targetPath), err)
-					continue
-				}
-				if err := s.extractSingleFileFromZip(file, targetPath); err != nil {
-					log.Printf("Warning: Failed to extract %s: %v", targetPath, err)
-				}
-			}
-		}
-	}
-	
-	// Second pass: extract the main binary
-	for _, file := range reader.File {
-		// Look for piper.exe specifically, avoid directories and other files
-		if file.FileInfo().IsDir() {
-			continue
-		}
-		fileName := strings.ToLower(filepath.Base(file.Name))
-		if fileName == "piper.exe" || (fileName == "piper" && filepath.Ext(fileName) == "") {
-			log.Printf("Found Piper binary: %s", file.Name)
-			err := s.extractSingleFileFromZip(file, s.config.PiperPath)
-			if err == nil {
-				log.Printf("Extracted %d DLL dependencies and piper binary successfully", extractedFiles)
-			}
-			return err
-		}
-	}
-	
-	return fmt.Errorf("piper binary not found in archive")
-}
-
-// extractTarGz extracts piper from a tar.gz archive
-func (s *TTSService) extractTarGz(archivePath string) error {
-	file, err := os.Open(archivePath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	gzReader, err := gzip.NewReader(file)
-	if err != nil {
-		return err
-	}
-	defer gzReader.Close()
-
-	tarReader := tar.NewReader(gzReader)
+	extractedDeps := 0
+	foundBinary := false
 
 	for {
-		header, err := tarReader.Next()
+		entry, err := extractor.Next()
 		if err == io.EOF {
 			break
 		}
@@ -832,45 +1151,63 @@ func (s *TTSService) extractTarGz(archivePath string) error {
 			return err
 		}
 
-		if header.Typeflag == tar.TypeReg {
-			fileName := strings.ToLower(filepath.Base(header.Name))
-			if fileName == "piper" && filepath.Ext(fileName) == "" {
-				log.Printf("Found Piper binary: %s", header.Name)
-				return s.extractSingleFileFromTar(tarReader, s.config.PiperPath)
+		baseName := strings.ToLower(filepath.Base(entry.Name))
+
+		if !entry.IsDir && isRequiredPiperDLL(baseName) {
+			dllPath, err := safeJoin(binDir, baseName)
+			if err != nil {
+				log.Printf("Warning: %v", err)
+				continue
+			}
+			if err := extractEntryTo(entry, dllPath); err != nil {
+				log.Printf("Warning: Failed to extract %s: %v", baseName, err)
+			} else {
+				log.Printf("Extracted required DLL: %s", dllPath)
+				extractedDeps++
 			}
+			continue
 		}
-	}
-	
-	return fmt.Errorf("piper binary not found in archive")
-}
 
-// extractSingleFileFromZip extracts a single file from ZIP
-func (s *TTSService) extractSingleFileFromZip(file *zip.File, outputPath string) error {
-	rc, err := file.Open()
-	if err != nil {
-		return err
-	}
-	defer rc.Close()
+		if strings.HasPrefix(entry.Name, "piper/espeak-ng-data/") {
+			relativePath := strings.TrimPrefix(entry.Name, "piper/")
+			targetPath, err := safeJoin(binDir, relativePath)
+			if err != nil {
+				log.Printf("Warning: %v", err)
+				continue
+			}
+			if entry.IsDir {
+				if err := os.MkdirAll(targetPath, 0755); err != nil {
+					log.Printf("Warning: Failed to create directory %s: %v", targetPath, err)
+				}
+			} else if err := extractEntryTo(entry, targetPath); err != nil {
+				log.Printf("Warning: Failed to extract %s: %v", targetPath, err)
+			}
+			continue
+		}
 
-	outFile, err := os.Create(outputPath)
-	if err != nil {
-		return err
+		if !entry.IsDir && (baseName == "piper.exe" || (baseName == "piper" && filepath.Ext(baseName) == "")) {
+			log.Printf("Found Piper binary: %s", entry.Name)
+			if err := extractEntryTo(entry, s.config.PiperPath); err != nil {
+				return err
+			}
+			log.Printf("Extracted %d DLL dependencies and piper binary successfully", extractedDeps)
+			foundBinary = true
+		}
 	}
-	defer outFile.Close()
 
-	_, err = io.Copy(outFile, rc)
-	if err != nil {
-		return err
+	if !foundBinary {
+		return fmt.Errorf("piper binary not found in archive")
 	}
+	return nil
+}
 
-	// Make executable on Unix systems
-	if runtime.GOOS != "windows" {
-		if err := os.Chmod(outputPath, 0755); err != nil {
-			return err
+func isRequiredPiperDLL(baseName string) bool {
+	for _, dll := range requiredPiperDLLs {
+		if baseName == dll {
+			return true
 		}
 	}
-
-	return nil
+	return false
 }
 
 // GetDefaultVoice returns the current default voice
@@ -907,72 +1244,84 @@ func (s *TTSService) GetAvailableVoices() []string {
 	return voices
 }
 
-// extractSingleFileFromTar extracts a single file from tar
-func (s *TTSService) extractSingleFileFromTar(tarReader *tar.Reader, outputPath string) error {
-	outFile, err := os.Create(outputPath)
-	if err != nil {
-		return err
+// DownloadVoice launches (or returns the already-running) background
+// download job for a catalog voice's .onnx model, verifying its MD5 digest
+// (the only checksum the upstream manifest publishes) before it's renamed
+// into place. The matching .onnx.json config is fetched alongside it with a
+// plain best-effort download, since the manifest doesn't checksum it.
+func (s *TTSService) DownloadVoice(ctx context.Context, name string) (*downloader.Job, error) {
+	if s.backend != nil {
+		return nil, fmt.Errorf("voice model downloads are not applicable to the %s backend", s.config.Provider)
 	}
-	defer outFile.Close()
 
-	_, err = io.Copy(outFile, tarReader)
+	voice, err := s.catalog.Lookup(ctx, name)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if voice.DownloadURL == "" {
+		return nil, fmt.Errorf("voice %s has no downloadable .onnx file in the catalog", name)
 	}
 
-	// Make executable on Unix systems
-	if runtime.GOOS != "windows" {
-		if err := os.Chmod(outputPath, 0755); err != nil {
-			return err
+	modelDir := s.config.ModelPath
+	if modelDir == "" {
+		modelDir = "models/piper"
+	}
+
+	configFile := filepath.Join(modelDir, name+".onnx.json")
+	if _, err := os.Stat(configFile); err != nil {
+		if err := s.downloadFile(voice.DownloadURL+".json", configFile); err != nil {
+			log.Printf("Warning: failed to download voice config for %s: %v", name, err)
 		}
 	}
 
-	return nil
+	modelFile := filepath.Join(modelDir, name+".onnx")
+	return s.downloads.StartMD5(name, voice.DownloadURL, modelFile, voice.MD5), nil
 }
 
-// downloadVoiceModel downloads a voice model from HuggingFace
-func (s *TTSService) downloadVoiceModel(voiceName, modelDir string) error {
-	// HuggingFace URLs for Piper voice models
-	baseURL := "https://huggingface.co/rhasspy/piper-voices/resolve/v1.0.0/en/en_US"
-	
-	// Map voice names to their paths
-	voicePaths := map[string]string{
-		"en_US-amy-medium":        "amy/medium",
-		"en_US-hfc_female-medium": "hfc_female/medium",
-		"en_US-kristin-medium":    "kristin/medium",
+// InstallVoice downloads and verifies name's model from the catalog (if it
+// isn't already present), blocking until it's ready to use. This is the
+// synchronous counterpart to DownloadVoice, for callers (like the voice
+// browser UI) that just want "voice ready" rather than a job id to poll.
+func (s *TTSService) InstallVoice(ctx context.Context, name string) error {
+	modelDir := s.config.ModelPath
+	if modelDir == "" {
+		modelDir = "models/piper"
 	}
-	
-	voicePath, exists := voicePaths[voiceName]
-	if !exists {
-		return fmt.Errorf("unknown voice: %s", voiceName)
+	if _, err := os.Stat(filepath.Join(modelDir, name+".onnx")); err == nil && s.backend == nil {
+		return nil
 	}
-	
-	// Special handling for en_GB voices
-	voiceURL := baseURL
-	if strings.HasPrefix(voiceName, "en_GB") {
-		voiceURL = "https://huggingface.co/rhasspy/piper-voices/resolve/v1.0.0/en/en_GB"
+
+	job, err := s.DownloadVoice(ctx, name)
+	if err != nil {
+		return err
 	}
-	
-	// Download both .onnx and .onnx.json files
-	onnxURL := fmt.Sprintf("%s/%s/%s.onnx", voiceURL, voicePath, voiceName)
-	jsonURL := fmt.Sprintf("%s/%s/%s.onnx.json", voiceURL, voicePath, voiceName)
-	
-	onnxFile := filepath.Join(modelDir, voiceName+".onnx")
-	jsonFile := filepath.Join(modelDir, voiceName+".onnx.json")
-	
-	// Download .onnx file
-	log.Printf("Downloading voice model: %s", onnxURL)
-	if err := s.downloadFile(onnxURL, onnxFile); err != nil {
-		return fmt.Errorf("failed to download .onnx file: %w", err)
+	return s.waitForDownload(ctx, job)
+}
+
+// ListCatalogVoices returns every voice published in the upstream voice
+// catalog (rather than just the ones already downloaded), optionally
+// filtered by language code.
+func (s *TTSService) ListCatalogVoices(ctx context.Context, lang string) ([]*Voice, error) {
+	return s.catalog.List(ctx, lang)
+}
+
+// VoiceDownloadStatus returns the tracked download job for a voice, if one
+// has been started.
+func (s *TTSService) VoiceDownloadStatus(name string) (*downloader.Job, bool) {
+	return s.downloads.Status(name)
+}
+
+// SelectVoice ensures the named voice's model is present on disk, then makes
+// it the default used for synthesis going forward, without restarting the
+// service. It is the TTS analogue of STTService.SelectModel.
+func (s *TTSService) SelectVoice(ctx context.Context, name string) error {
+	if s.backend != nil {
+		return s.SetDefaultVoice(name)
 	}
-	
-	// Download .onnx.json file
-	log.Printf("Downloading voice config: %s", jsonURL)
-	if err := s.downloadFile(jsonURL, jsonFile); err != nil {
-		return fmt.Errorf("failed to download .onnx.json file: %w", err)
+	if err := s.ensureVoiceModel(ctx, name); err != nil {
+		return fmt.Errorf("voice %s is not available: %w", name, err)
 	}
-	
-	return nil
+	return s.SetDefaultVoice(name)
 }
 
 // Shutdown gracefully shuts down the TTS service
@@ -980,6 +1329,23 @@ func (s *TTSService) Shutdown(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.onnxEngine != nil {
+		if err := s.onnxEngine.Close(); err != nil {
+			return fmt.Errorf("failed to close onnx piper engine: %w", err)
+		}
+		s.onnxEngine = nil
+	}
+
+	if s.pool != nil {
+		s.pool.Close()
+	}
+
+	if s.backend != nil {
+		if err := s.backend.Close(); err != nil {
+			return fmt.Errorf("failed to close %s backend: %w", s.config.Provider, err)
+		}
+	}
+
 	s.ready = false
 	s.info.Status = "stopped"
 	s.info.LastUpdated = time.Now()
@@ -0,0 +1,54 @@
+package piper
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// parseWAV reads the PCM samples, sample rate and channel count out of a
+// canonical RIFF/WAVE file, mirroring whisper.wavDecoder. It only supports
+// 16-bit PCM, which is all Piper (and generatePlaceholderWAV) ever produce.
+func parseWAV(data []byte) (samples []int16, sampleRate, channels int, err error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, 0, 0, errors.New("not a RIFF/WAVE file")
+	}
+
+	var (
+		bitsPerSample int
+		pcm           []byte
+	)
+
+	pos := 12
+	for pos+8 <= len(data) {
+		chunkID := string(data[pos : pos+4])
+		chunkSize := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		chunkStart := pos + 8
+		if chunkStart+chunkSize > len(data) {
+			break
+		}
+
+		switch chunkID {
+		case "fmt ":
+			channels = int(binary.LittleEndian.Uint16(data[chunkStart+2 : chunkStart+4]))
+			sampleRate = int(binary.LittleEndian.Uint32(data[chunkStart+4 : chunkStart+8]))
+			bitsPerSample = int(binary.LittleEndian.Uint16(data[chunkStart+14 : chunkStart+16]))
+		case "data":
+			pcm = data[chunkStart : chunkStart+chunkSize]
+		}
+
+		pos = chunkStart + chunkSize
+		if chunkSize%2 == 1 {
+			pos++ // chunks are word-aligned
+		}
+	}
+
+	if pcm == nil || channels == 0 || bitsPerSample != 16 {
+		return nil, 0, 0, errors.New("missing fmt/data chunk or unsupported bit depth")
+	}
+
+	samples = make([]int16, len(pcm)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(pcm[i*2 : i*2+2]))
+	}
+	return samples, sampleRate, channels, nil
+}
@@ -1,13 +1,18 @@
 package server
 
 import (
+	"bufio"
 	"context"
-	"log"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
 	"time"
 
 	"alice-backend/internal/api"
+	"alice-backend/internal/apierr"
 	"alice-backend/internal/config"
 
 	"github.com/gorilla/mux"
@@ -17,22 +22,57 @@ import (
 type Server struct {
 	httpServer *http.Server
 	handler    *api.Handler
+	config     *config.Config
 }
 
 // NewServer creates a new HTTP server
 func NewServer(config *config.Config, handler *api.Handler) *Server {
 	return &Server{
 		handler: handler,
+		config:  config,
 	}
 }
 
-// Start starts the HTTP server
+// Start starts the HTTP server, listening on port itself.
 func (s *Server) Start(port string) error {
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return fmt.Errorf("failed to listen on port %s: %w", port, err)
+	}
+	return s.Serve(listener)
+}
+
+// Serve starts the HTTP server on an already-open listener instead of
+// binding its own, so a live-reload handoff (see main.go's SIGUSR2
+// handling) can pass down the listening socket's file descriptor to a new
+// process and have both serve the same port concurrently during the
+// handoff window.
+func (s *Server) Serve(listener net.Listener) error {
+	router := s.buildRouter()
+	handler := corsMiddleware(router)
+
+	s.httpServer = &http.Server{
+		Handler:      handler,
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	slog.Info("Server starting", "address", listener.Addr())
+	return s.httpServer.Serve(listener)
+}
+
+// buildRouter assembles the route table; factored out of Start so Serve can
+// reuse it regardless of where the listener came from.
+func (s *Server) buildRouter() *mux.Router {
 	router := mux.NewRouter()
 
 	// Add middleware
-	router.Use(loggingMiddleware)
+	router.Use(api.RequestIDMiddleware)
+	router.Use(structuredLoggingMiddleware)
 	router.Use(recoveryMiddleware)
+	router.Use(api.TimeoutMiddleware(s.config))
+	router.Use(api.ModelRoutingMiddleware)
 
 	// API routes
 	apiRouter := router.PathPrefix("/api").Subrouter()
@@ -40,48 +80,77 @@ func (s *Server) Start(port string) error {
 	// Health check
 	apiRouter.HandleFunc("/health", s.handler.HealthCheck).Methods("GET")
 	apiRouter.HandleFunc("/config", s.handler.GetConfig).Methods("GET")
+	apiRouter.HandleFunc("/status", s.handler.GetStatus).Methods("GET")
+	router.HandleFunc("/metrics", s.handler.Metrics).Methods("GET")
+
+	metricsRecorder := s.handler.ModelManager().GetMetricsRecorder()
 
 	// STT routes
 	sttRouter := apiRouter.PathPrefix("/stt").Subrouter()
+	sttRouter.Use(api.MetricsMiddleware(metricsRecorder))
 	sttRouter.HandleFunc("/transcribe", s.handler.TranscribeAudio).Methods("POST")
 	sttRouter.HandleFunc("/transcribe-audio", s.handler.TranscribeAudio).Methods("POST")
 	sttRouter.HandleFunc("/transcribe-file", s.handler.TranscribeAudio).Methods("POST")
 	sttRouter.HandleFunc("/ready", s.handler.STTReady).Methods("GET")
 	sttRouter.HandleFunc("/info", s.handler.STTInfo).Methods("GET")
+	sttRouter.HandleFunc("/stream", s.handler.TranscribeStreamWS)
 
 	// TTS routes
 	ttsRouter := apiRouter.PathPrefix("/tts").Subrouter()
+	ttsRouter.Use(api.MetricsMiddleware(metricsRecorder))
 	ttsRouter.HandleFunc("/synthesize", s.handler.SynthesizeSpeech).Methods("POST")
 	ttsRouter.HandleFunc("/voices", s.handler.GetVoices).Methods("GET")
 	ttsRouter.HandleFunc("/ready", s.handler.TTSReady).Methods("GET")
 	ttsRouter.HandleFunc("/info", s.handler.TTSInfo).Methods("GET")
+	ttsRouter.HandleFunc("/stream", s.handler.SynthesizeSpeechStream).Methods("POST")
+	ttsRouter.HandleFunc("/voices/available", s.handler.ListCatalogVoices).Methods("GET")
+	ttsRouter.HandleFunc("/voices/install", s.handler.InstallVoice).Methods("POST")
+	ttsRouter.HandleFunc("/voices/install/{id}", s.handler.InstallVoiceByID).Methods("POST")
+	ttsRouter.HandleFunc("/voices/{id}", s.handler.RemoveVoice).Methods("DELETE")
 
 	// Embeddings routes
 	embeddingsRouter := apiRouter.PathPrefix("/embeddings").Subrouter()
+	embeddingsRouter.Use(api.MetricsMiddleware(metricsRecorder))
 	embeddingsRouter.HandleFunc("/generate", s.handler.GenerateEmbedding).Methods("POST")
 	embeddingsRouter.HandleFunc("/batch", s.handler.GenerateEmbeddings).Methods("POST")
 	embeddingsRouter.HandleFunc("/generate-batch", s.handler.GenerateEmbeddings).Methods("POST")
+	embeddingsRouter.HandleFunc("/batch/stream", s.handler.GenerateEmbeddingsStream).Methods("POST")
 	embeddingsRouter.HandleFunc("/ready", s.handler.EmbeddingsReady).Methods("GET")
 	embeddingsRouter.HandleFunc("/info", s.handler.EmbeddingsInfo).Methods("GET")
 
+	// Persistent vector store ("collections") routes, built on top of the
+	// embeddings service above.
+	collectionsRouter := apiRouter.PathPrefix("/embeddings/collections/{name}").Subrouter()
+	collectionsRouter.HandleFunc("/upsert", s.handler.UpsertVector).Methods("POST")
+	collectionsRouter.HandleFunc("/query", s.handler.QueryVectorCollection).Methods("POST")
+	collectionsRouter.HandleFunc("/stats", s.handler.GetCollectionStats).Methods("GET")
+	collectionsRouter.HandleFunc("/{id}", s.handler.DeleteVector).Methods("DELETE")
+
+	// Rerank route, built on top of the embeddings service
+	apiRouter.HandleFunc("/rerank", s.handler.Rerank).Methods("POST")
+
 	// Model management routes
 	modelsRouter := apiRouter.PathPrefix("/models").Subrouter()
 	modelsRouter.HandleFunc("/download/{service}", s.handler.DownloadModel).Methods("POST")
 	modelsRouter.HandleFunc("/status", s.handler.GetModelStatus).Methods("GET")
 	modelsRouter.HandleFunc("/download-status", s.handler.GetModelDownloadStatus).Methods("GET")
+	modelsRouter.HandleFunc("/download-events", s.handler.DownloadModelEvents).Methods("GET")
+	modelsRouter.HandleFunc("/{service}/select", s.handler.SelectModel).Methods("POST")
 
-	handler := corsMiddleware(router)
+	// OpenAI-compatible routes
+	v1Router := router.PathPrefix("/v1").Subrouter()
+	v1Router.HandleFunc("/audio/transcriptions", s.handler.TranscribeOpenAI).Methods("POST")
+	v1Router.HandleFunc("/tts/stream", s.handler.SynthesizeSpeechStream).Methods("POST")
+	v1Router.HandleFunc("/tts/voices", s.handler.ListCatalogVoices).Methods("GET")
+	v1Router.HandleFunc("/audio/speech", s.handler.SynthesizeSpeechOpenAI).Methods("POST")
 
-	s.httpServer = &http.Server{
-		Addr:         ":" + port,
-		Handler:      handler,
-		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 30 * time.Second,
-		IdleTimeout:  60 * time.Second,
-	}
+	// Per-request model selection via URL prefix, e.g.
+	// /v1/whisper-large/stt/transcribe or /v1/en_US-ryan-medium/tts/synthesize,
+	// as an alternative to the X-Alice-Model/X-Alice-Voice headers.
+	v1Router.HandleFunc("/{model}/stt/transcribe", api.WithURLModel(s.handler.TranscribeAudio)).Methods("POST")
+	v1Router.HandleFunc("/{model}/tts/synthesize", api.WithURLModel(s.handler.SynthesizeSpeech)).Methods("POST")
 
-	log.Printf("Server starting on port %s", port)
-	return s.httpServer.ListenAndServe()
+	return router
 }
 
 func corsMiddleware(next http.Handler) http.Handler {
@@ -117,23 +186,90 @@ func (s *Server) Stop(ctx context.Context) error {
 	return nil
 }
 
-// loggingMiddleware logs HTTP requests
-func loggingMiddleware(next http.Handler) http.Handler {
+// structuredLoggingMiddleware logs one JSON line per completed request via
+// log/slog (method, path, status, latency, bytes written, request id),
+// replacing the old plain-text "Started"/"Completed" log.Printf pair so
+// request logs can be parsed the same way as everything else slog already
+// logs (see main.go).
+func structuredLoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lw := &loggingResponseWriter{ResponseWriter: w, status: http.StatusOK}
 		start := time.Now()
-		log.Printf("Started %s %s", r.Method, r.URL.Path)
-		next.ServeHTTP(w, r)
-		log.Printf("Completed %s %s in %v", r.Method, r.URL.Path, time.Since(start))
+		next.ServeHTTP(lw, r)
+		slog.Info("http request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", lw.status,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"bytes", lw.bytes,
+			"request_id", api.RequestIDFromContext(r.Context()),
+		)
 	})
 }
 
-// recoveryMiddleware recovers from panics
+// loggingResponseWriter captures the status code and byte count
+// structuredLoggingMiddleware logs after the handler returns, since
+// http.ResponseWriter exposes neither.
+type loggingResponseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *loggingResponseWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *loggingResponseWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// Flush forwards to the wrapped writer's http.Flusher, so a streaming
+// handler downstream of this middleware (SSE progress events, NDJSON
+// embeddings, chunked TTS audio) still works instead of silently buffering
+// until the handler returns.
+func (w *loggingResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack forwards to the wrapped writer's http.Hijacker, so a handler that
+// needs the raw connection (the STT WebSocket upgrade) can still take over
+// it through this middleware.
+func (w *loggingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("underlying ResponseWriter does not support hijacking")
+	}
+	return hj.Hijack()
+}
+
+// recoveryMiddleware recovers from panics, logging the recovered value
+// alongside a captured stack trace and the request id (see
+// api.RequestIDMiddleware) before responding with the same JSON error
+// envelope a normal handler error would produce.
 func recoveryMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
-			if err := recover(); err != nil {
-				log.Printf("Panic recovered: %v", err)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			if rec := recover(); rec != nil {
+				apiErr := apierr.New(apierr.ErrInternal, http.StatusInternalServerError, "Internal Server Error")
+				requestID := api.RequestIDFromContext(r.Context())
+				slog.Error("Panic recovered", "error", rec, "request_id", requestID, "stack", apiErr.StackString())
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(apiErr.HTTPStatus)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"success": false,
+					"error": map[string]interface{}{
+						"code":       apiErr.Code,
+						"message":    apiErr.Message,
+						"request_id": requestID,
+					},
+				})
 			}
 		}()
 		next.ServeHTTP(w, r)
@@ -0,0 +1,359 @@
+// Package services holds cross-cutting infrastructure shared by the model
+// backends in models.Manager, as opposed to internal/whisper, internal/piper,
+// and internal/minilm, which each implement one specific backend.
+package services
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// circuitState is the state of a single backend's circuit breaker.
+type circuitState string
+
+const (
+	circuitClosed   circuitState = "closed"    // serving requests normally
+	circuitOpen     circuitState = "open"      // tripped, requests get a clean 503
+	circuitHalfOpen circuitState = "half_open" // probing: requests allowed through until the next recorded result decides
+)
+
+// maxLatencySamples bounds the per-backend ring buffer used for p50/p95, so
+// a long-running backend doesn't grow this without bound.
+const maxLatencySamples = 256
+
+// backendStats is the monitor's internal bookkeeping for one named backend
+// (e.g. "stt", "tts", "embeddings").
+type backendStats struct {
+	mu sync.Mutex
+
+	latencies []time.Duration // ring buffer, oldest overwritten first
+	next      int
+	requests  int64
+	errors    int64
+
+	state        circuitState
+	nextRetry    time.Time
+	retryBackoff time.Duration
+}
+
+// BackendStatus is a point-in-time snapshot of one backend's health, as
+// returned by BackendMonitor.Snapshot and served from GET /api/status.
+type BackendStatus struct {
+	Name        string        `json:"name"`
+	State       string        `json:"state"`
+	Requests    int64         `json:"requests"`
+	Errors      int64         `json:"errors"`
+	ErrorRate   float64       `json:"error_rate"`
+	P50Latency  time.Duration `json:"p50_latency_ms"`
+	P95Latency  time.Duration `json:"p95_latency_ms"`
+	NextRetryIn time.Duration `json:"next_retry_in_ms,omitempty"`
+}
+
+// ResourceSample is a coarse point-in-time resource reading for the server
+// process as a whole. True per-backend CPU/RSS/GPU-VRAM accounting would
+// need OS-specific APIs (or an NVML binding for GPU VRAM) that aren't
+// vendored in this tree, so this reports the Go runtime's own heap stats as
+// a process-wide proxy instead of fabricating per-backend numbers.
+type ResourceSample struct {
+	HeapAllocBytes uint64    `json:"heap_alloc_bytes"`
+	HeapSysBytes   uint64    `json:"heap_sys_bytes"`
+	NumGoroutine   int       `json:"num_goroutine"`
+	SampledAt      time.Time `json:"sampled_at"`
+}
+
+// BackendMonitor tracks latency, error rate, and circuit-breaker state per
+// named backend, and a coarse process-wide resource sample, for exposure via
+// GET /api/status and GET /metrics.
+type BackendMonitor struct {
+	errorRateThreshold float64 // trip the circuit once errors/requests exceeds this, given enough samples
+	minSamples         int64   // don't trip on error rate until at least this many requests have been seen
+	initialBackoff     time.Duration
+	maxBackoff         time.Duration
+
+	mu       sync.Mutex
+	backends map[string]*backendStats
+	resource ResourceSample
+}
+
+// NewBackendMonitor creates a BackendMonitor. errorRateThreshold is a
+// fraction in [0,1]; initialBackoff/maxBackoff bound the exponential backoff
+// applied between re-initialization attempts once a backend's circuit trips.
+func NewBackendMonitor(errorRateThreshold float64, initialBackoff, maxBackoff time.Duration) *BackendMonitor {
+	if errorRateThreshold <= 0 {
+		errorRateThreshold = 0.5
+	}
+	if initialBackoff <= 0 {
+		initialBackoff = time.Second
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = 5 * time.Minute
+	}
+	return &BackendMonitor{
+		errorRateThreshold: errorRateThreshold,
+		minSamples:         5,
+		initialBackoff:     initialBackoff,
+		maxBackoff:         maxBackoff,
+		backends:           make(map[string]*backendStats),
+	}
+}
+
+func (m *BackendMonitor) statsFor(name string) *backendStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.backends[name]
+	if !ok {
+		s = &backendStats{state: circuitClosed}
+		m.backends[name] = s
+	}
+	return s
+}
+
+// RecordResult records the outcome of one request served by the named
+// backend and trips its circuit breaker if the error rate crosses
+// errorRateThreshold.
+func (m *BackendMonitor) RecordResult(name string, latency time.Duration, err error) {
+	s := m.statsFor(name)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.latencies) < maxLatencySamples {
+		s.latencies = append(s.latencies, latency)
+	} else {
+		s.latencies[s.next%maxLatencySamples] = latency
+	}
+	s.next++
+	s.requests++
+	if err != nil {
+		s.errors++
+	}
+
+	if s.state == circuitClosed && s.requests >= m.minSamples {
+		if float64(s.errors)/float64(s.requests) > m.errorRateThreshold {
+			m.tripLocked(s)
+		}
+	}
+	if s.state == circuitHalfOpen {
+		if err != nil {
+			m.tripLocked(s)
+		} else {
+			s.state = circuitClosed
+			s.requests, s.errors = 0, 0
+			s.retryBackoff = 0
+		}
+	}
+}
+
+// tripLocked opens the circuit and schedules the next retry. Callers must
+// hold s.mu.
+func (m *BackendMonitor) tripLocked(s *backendStats) {
+	s.state = circuitOpen
+	if s.retryBackoff == 0 {
+		s.retryBackoff = m.initialBackoff
+	} else {
+		s.retryBackoff *= 2
+		if s.retryBackoff > m.maxBackoff {
+			s.retryBackoff = m.maxBackoff
+		}
+	}
+	s.nextRetry = time.Now().Add(s.retryBackoff)
+}
+
+// Trip immediately opens name's circuit, e.g. when Manager detects the
+// backend's process has exited outright rather than just erroring.
+func (m *BackendMonitor) Trip(name string) {
+	s := m.statsFor(name)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m.tripLocked(s)
+}
+
+// Allow reports whether a request to the named backend should be let
+// through. A backend with an open circuit and an elapsed backoff is moved to
+// half-open and the request is allowed as a probe. While half-open, every
+// further request is also allowed, until the next RecordResult closes the
+// circuit or trips it again; the number of concurrent probes is not limited.
+// An open circuit whose backoff hasn't elapsed refuses requests, so callers
+// can answer with a clean 503 instead of hanging.
+func (m *BackendMonitor) Allow(name string) bool {
+	s := m.statsFor(name)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch s.state {
+	case circuitOpen:
+		if time.Now().After(s.nextRetry) {
+			s.state = circuitHalfOpen
+			return true
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// TrippedBackends returns the names of backends whose circuit is currently
+// open and whose backoff has elapsed, i.e. those models.Manager should
+// attempt to re-initialize next.
+func (m *BackendMonitor) TrippedBackends() []string {
+	m.mu.Lock()
+	names := make([]string, 0, len(m.backends))
+	for name := range m.backends {
+		names = append(names, name)
+	}
+	m.mu.Unlock()
+
+	var due []string
+	for _, name := range names {
+		s := m.statsFor(name)
+		s.mu.Lock()
+		if s.state == circuitOpen && time.Now().After(s.nextRetry) {
+			due = append(due, name)
+		}
+		s.mu.Unlock()
+	}
+	sort.Strings(due)
+	return due
+}
+
+// MarkRecovered resets name's circuit to closed after Manager has
+// successfully re-initialized it.
+func (m *BackendMonitor) MarkRecovered(name string) {
+	s := m.statsFor(name)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = circuitClosed
+	s.requests, s.errors = 0, 0
+	s.retryBackoff = 0
+}
+
+// MarkRecoveryFailed keeps name's circuit open and reschedules the next
+// retry with exponential backoff after a re-initialization attempt failed.
+func (m *BackendMonitor) MarkRecoveryFailed(name string) {
+	s := m.statsFor(name)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m.tripLocked(s)
+}
+
+// SampleResources records a coarse process-wide resource reading. Manager
+// calls this on a timer; see ResourceSample's doc comment for why this is
+// process-wide rather than per-backend.
+func (m *BackendMonitor) SampleResources() {
+	var mstats runtime.MemStats
+	runtime.ReadMemStats(&mstats)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.resource = ResourceSample{
+		HeapAllocBytes: mstats.HeapAlloc,
+		HeapSysBytes:   mstats.HeapSys,
+		NumGoroutine:   runtime.NumGoroutine(),
+		SampledAt:      time.Now(),
+	}
+}
+
+// Snapshot returns the current status of every backend seen so far, plus
+// the latest resource sample.
+func (m *BackendMonitor) Snapshot() ([]BackendStatus, ResourceSample) {
+	m.mu.Lock()
+	names := make([]string, 0, len(m.backends))
+	for name := range m.backends {
+		names = append(names, name)
+	}
+	resource := m.resource
+	m.mu.Unlock()
+	sort.Strings(names)
+
+	statuses := make([]BackendStatus, 0, len(names))
+	for _, name := range names {
+		s := m.statsFor(name)
+		s.mu.Lock()
+		p50, p95 := percentiles(s.latencies)
+		status := BackendStatus{
+			Name:       name,
+			State:      string(s.state),
+			Requests:   s.requests,
+			Errors:     s.errors,
+			P50Latency: p50,
+			P95Latency: p95,
+		}
+		if s.requests > 0 {
+			status.ErrorRate = float64(s.errors) / float64(s.requests)
+		}
+		if s.state == circuitOpen {
+			if d := time.Until(s.nextRetry); d > 0 {
+				status.NextRetryIn = d
+			}
+		}
+		s.mu.Unlock()
+		statuses = append(statuses, status)
+	}
+	return statuses, resource
+}
+
+// percentiles returns the p50 and p95 of samples without mutating it.
+func percentiles(samples []time.Duration) (p50, p95 time.Duration) {
+	if len(samples) == 0 {
+		return 0, 0
+	}
+	sorted := make([]time.Duration, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	p50 = sorted[(len(sorted)*50)/100]
+	p95 = sorted[(len(sorted)*95)/100]
+	return p50, p95
+}
+
+// WritePrometheus writes the monitor's current snapshot in Prometheus text
+// exposition format, for GET /metrics. There's no vendored Prometheus client
+// library in this tree, so this formats the handful of gauges by hand rather
+// than pulling in a new dependency for a handful of metric lines.
+func (m *BackendMonitor) WritePrometheus(w writer) {
+	statuses, resource := m.Snapshot()
+
+	fmt.Fprintln(w, "# HELP alice_backend_requests_total Total requests served by backend")
+	fmt.Fprintln(w, "# TYPE alice_backend_requests_total counter")
+	for _, s := range statuses {
+		fmt.Fprintf(w, "alice_backend_requests_total{backend=%q} %d\n", s.Name, s.Requests)
+	}
+
+	fmt.Fprintln(w, "# HELP alice_backend_errors_total Total request errors by backend")
+	fmt.Fprintln(w, "# TYPE alice_backend_errors_total counter")
+	for _, s := range statuses {
+		fmt.Fprintf(w, "alice_backend_errors_total{backend=%q} %d\n", s.Name, s.Errors)
+	}
+
+	fmt.Fprintln(w, "# HELP alice_backend_latency_p95_milliseconds p95 request latency by backend")
+	fmt.Fprintln(w, "# TYPE alice_backend_latency_p95_milliseconds gauge")
+	for _, s := range statuses {
+		fmt.Fprintf(w, "alice_backend_latency_p95_milliseconds{backend=%q} %d\n", s.Name, s.P95Latency.Milliseconds())
+	}
+
+	fmt.Fprintln(w, "# HELP alice_backend_circuit_open Whether a backend's circuit breaker is currently open")
+	fmt.Fprintln(w, "# TYPE alice_backend_circuit_open gauge")
+	for _, s := range statuses {
+		open := 0
+		if s.State == string(circuitOpen) {
+			open = 1
+		}
+		fmt.Fprintf(w, "alice_backend_circuit_open{backend=%q} %d\n", s.Name, open)
+	}
+
+	fmt.Fprintln(w, "# HELP alice_process_heap_alloc_bytes Go runtime heap bytes in use, as a coarse process-wide resource proxy")
+	fmt.Fprintln(w, "# TYPE alice_process_heap_alloc_bytes gauge")
+	fmt.Fprintf(w, "alice_process_heap_alloc_bytes %d\n", resource.HeapAllocBytes)
+
+	fmt.Fprintln(w, "# HELP alice_process_goroutines Number of live goroutines")
+	fmt.Fprintln(w, "# TYPE alice_process_goroutines gauge")
+	fmt.Fprintf(w, "alice_process_goroutines %d\n", resource.NumGoroutine)
+}
+
+// writer is the minimal io.Writer subset WritePrometheus needs, so this
+// package doesn't have to import net/http or io just for the interface.
+type writer interface {
+	Write(p []byte) (n int, err error)
+}
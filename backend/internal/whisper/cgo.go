@@ -0,0 +1,108 @@
+//go:build whisper_cgo
+
+package whisper
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	gowhisper "github.com/ggerganov/whisper.cpp/bindings/go/pkg/whisper"
+)
+
+// cgoEngine holds a whisper.cpp model loaded once via the official cgo
+// bindings, so transcription runs in-process instead of shelling out to the
+// whisper-cli binary for every utterance.
+type cgoEngine struct {
+	model gowhisper.Model
+}
+
+// newCgoEngine loads modelPath into memory through libwhisper.
+func newCgoEngine(modelPath string) (*cgoEngine, error) {
+	model, err := gowhisper.New(modelPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load whisper model: %w", err)
+	}
+	return &cgoEngine{model: model}, nil
+}
+
+// transcribe runs a single, synchronous transcription over samples using a
+// fresh whisper.cpp context so concurrent calls don't share decoder state.
+func (e *cgoEngine) transcribe(ctx context.Context, samples []float32, cfg *Config, decoding DecodingOptions) (*TranscribeResult, error) {
+	wctx, err := e.model.NewContext()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create whisper context: %w", err)
+	}
+
+	if cfg.Language != "" && cfg.Language != "auto" {
+		if err := wctx.SetLanguage(cfg.Language); err != nil {
+			return nil, fmt.Errorf("failed to set language: %w", err)
+		}
+	}
+
+	applyDecodingOptions(wctx, decoding)
+
+	if err := wctx.Process(samples, nil, nil, nil); err != nil {
+		return nil, fmt.Errorf("whisper processing failed: %w", err)
+	}
+
+	result := &TranscribeResult{}
+	for {
+		seg, err := wctx.NextSegment()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read segment: %w", err)
+		}
+
+		tokens := make([]Token, len(seg.Tokens))
+		for i, t := range seg.Tokens {
+			tokens[i] = Token{Id: t.Id, Text: t.Text, P: t.P, Start: t.Start, End: t.End}
+		}
+
+		result.Segments = append(result.Segments, Segment{
+			Text:   seg.Text,
+			Start:  seg.Start,
+			End:    seg.End,
+			Tokens: tokens,
+		})
+		result.Text += seg.Text
+	}
+
+	return result, nil
+}
+
+// applyDecodingOptions maps the subset of DecodingOptions the official
+// bindings expose onto the whisper.cpp context. Fields with no bindings
+// equivalent (BestOf, NoSpeechThreshold, DisableTemperatureFallback,
+// SpeakerTurnDetection) are left to the binary backend; SuppressBlank is
+// applied by neither backend.
+func applyDecodingOptions(wctx gowhisper.Context, opts DecodingOptions) {
+	if opts.BeamSize > 0 {
+		wctx.SetBeamSize(opts.BeamSize)
+	}
+	if opts.Temperature > 0 {
+		wctx.SetTemperature(float32(opts.Temperature))
+	}
+	if opts.TemperatureIncrement > 0 {
+		wctx.SetTemperatureFallback(float32(opts.TemperatureIncrement))
+	}
+	if opts.EntropyThreshold > 0 {
+		wctx.SetEntropyThold(float32(opts.EntropyThreshold))
+	}
+	if opts.InitialPrompt != "" {
+		wctx.SetInitialPrompt(opts.InitialPrompt)
+	}
+	if opts.Translate {
+		wctx.SetTranslate(true)
+	}
+	if opts.WordTimestamps {
+		wctx.SetTokenTimestamps(true)
+	}
+}
+
+// Close releases the underlying whisper.cpp model.
+func (e *cgoEngine) Close() error {
+	return e.model.Close()
+}
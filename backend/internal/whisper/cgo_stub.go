@@ -0,0 +1,25 @@
+//go:build !whisper_cgo
+
+package whisper
+
+import (
+	"context"
+	"fmt"
+)
+
+// cgoEngine is a no-op placeholder for builds without the whisper_cgo tag
+// (the default), since the real implementation depends on libwhisper and a
+// C toolchain that aren't available in every build environment.
+type cgoEngine struct{}
+
+func newCgoEngine(modelPath string) (*cgoEngine, error) {
+	return nil, fmt.Errorf("whisper cgo backend not compiled in; rebuild with -tags whisper_cgo")
+}
+
+func (e *cgoEngine) transcribe(ctx context.Context, samples []float32, cfg *Config, decoding DecodingOptions) (*TranscribeResult, error) {
+	return nil, fmt.Errorf("whisper cgo backend not compiled in")
+}
+
+func (e *cgoEngine) Close() error {
+	return nil
+}
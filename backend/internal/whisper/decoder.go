@@ -0,0 +1,271 @@
+package whisper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hajimehoshi/go-mp3"
+	"github.com/jfreymuth/oggvorbis"
+	"github.com/mewkiz/flac"
+)
+
+// AudioDecoder decodes an audio container into PCM samples, reporting the
+// stream's native sample rate and channel count. Callers are responsible
+// for resampling/downmixing to whisper's expected 16kHz mono (see
+// resampleLinear and downmixToMono).
+type AudioDecoder interface {
+	Decode(r io.Reader) (samples []float32, sampleRate int, channels int, err error)
+}
+
+// detectAudioFormat sniffs the magic bytes at the start of an audio payload
+// to pick a decoder, so TranscribeAudio doesn't have to guess (and silently
+// mis-decode) based on a caller-supplied extension or content type.
+func detectAudioFormat(data []byte) string {
+	if len(data) < 12 {
+		return "unknown"
+	}
+
+	switch {
+	case bytes.Equal(data[0:4], []byte("RIFF")) && bytes.Equal(data[8:12], []byte("WAVE")):
+		return "wav"
+	case bytes.Equal(data[0:4], []byte("fLaC")):
+		return "flac"
+	case bytes.Equal(data[0:4], []byte("OggS")):
+		return "ogg"
+	case bytes.Equal(data[0:3], []byte("ID3")):
+		return "mp3"
+	case data[0] == 0xFF && data[1]&0xE0 == 0xE0:
+		return "mp3"
+	default:
+		return "unknown"
+	}
+}
+
+// wavDecoder reads the PCM samples out of a canonical RIFF/WAVE file.
+type wavDecoder struct{}
+
+func (wavDecoder) Decode(r io.Reader) ([]float32, int, int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, 0, 0, errors.New("not a RIFF/WAVE file")
+	}
+
+	var (
+		sampleRate    int
+		channels      int
+		bitsPerSample int
+		pcm           []byte
+	)
+
+	pos := 12
+	for pos+8 <= len(data) {
+		chunkID := string(data[pos : pos+4])
+		chunkSize := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		chunkStart := pos + 8
+		if chunkStart+chunkSize > len(data) {
+			break
+		}
+
+		switch chunkID {
+		case "fmt ":
+			channels = int(binary.LittleEndian.Uint16(data[chunkStart+2 : chunkStart+4]))
+			sampleRate = int(binary.LittleEndian.Uint32(data[chunkStart+4 : chunkStart+8]))
+			bitsPerSample = int(binary.LittleEndian.Uint16(data[chunkStart+14 : chunkStart+16]))
+		case "data":
+			pcm = data[chunkStart : chunkStart+chunkSize]
+		}
+
+		pos = chunkStart + chunkSize
+		if chunkSize%2 == 1 {
+			pos++ // chunks are word-aligned
+		}
+	}
+
+	if pcm == nil || channels == 0 || bitsPerSample == 0 {
+		return nil, 0, 0, errors.New("missing fmt or data chunk")
+	}
+
+	samples, err := pcmBytesToFloat32(pcm, bitsPerSample)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return samples, sampleRate, channels, nil
+}
+
+// pcmBytesToFloat32 converts little-endian integer PCM to normalized
+// float32 samples in [-1, 1].
+func pcmBytesToFloat32(pcm []byte, bitsPerSample int) ([]float32, error) {
+	switch bitsPerSample {
+	case 8:
+		samples := make([]float32, len(pcm))
+		for i, b := range pcm {
+			samples[i] = (float32(b) - 128) / 128.0
+		}
+		return samples, nil
+	case 16:
+		if len(pcm)%2 != 0 {
+			return nil, errors.New("invalid 16-bit PCM: odd number of bytes")
+		}
+		samples := make([]float32, len(pcm)/2)
+		for i := range samples {
+			v := int16(binary.LittleEndian.Uint16(pcm[i*2 : i*2+2]))
+			samples[i] = float32(v) / 32768.0
+		}
+		return samples, nil
+	case 24:
+		if len(pcm)%3 != 0 {
+			return nil, errors.New("invalid 24-bit PCM: length not a multiple of 3")
+		}
+		samples := make([]float32, len(pcm)/3)
+		for i := range samples {
+			b0, b1, b2 := pcm[i*3], pcm[i*3+1], pcm[i*3+2]
+			v := int32(b0) | int32(b1)<<8 | int32(b2)<<16
+			if v&0x800000 != 0 {
+				v |= -1 << 24 // sign-extend
+			}
+			samples[i] = float32(v) / 8388608.0
+		}
+		return samples, nil
+	case 32:
+		if len(pcm)%4 != 0 {
+			return nil, errors.New("invalid 32-bit PCM: length not a multiple of 4")
+		}
+		samples := make([]float32, len(pcm)/4)
+		for i := range samples {
+			v := int32(binary.LittleEndian.Uint32(pcm[i*4 : i*4+4]))
+			samples[i] = float32(v) / 2147483648.0
+		}
+		return samples, nil
+	default:
+		return nil, fmt.Errorf("unsupported PCM bit depth: %d", bitsPerSample)
+	}
+}
+
+// mp3Decoder decodes MPEG audio layer 3 via a native Go decoder.
+type mp3Decoder struct{}
+
+func (mp3Decoder) Decode(r io.Reader) ([]float32, int, int, error) {
+	dec, err := mp3.NewDecoder(r)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	raw, err := io.ReadAll(dec)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// go-mp3 always decodes to interleaved 16-bit stereo PCM.
+	samples, err := pcmBytesToFloat32(raw, 16)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return samples, dec.SampleRate(), 2, nil
+}
+
+// flacDecoder decodes FLAC via a native Go decoder.
+type flacDecoder struct{}
+
+func (flacDecoder) Decode(r io.Reader) ([]float32, int, int, error) {
+	stream, err := flac.Parse(r)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	maxVal := float32(int32(1) << (stream.Info.BitsPerSample - 1))
+
+	var samples []float32
+	for {
+		frame, err := stream.ParseNext()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, 0, err
+		}
+
+		nChannels := len(frame.Subframes)
+		nSamples := frame.Subframes[0].NSamples
+		for i := 0; i < nSamples; i++ {
+			for ch := 0; ch < nChannels; ch++ {
+				samples = append(samples, float32(frame.Subframes[ch].Samples[i])/maxVal)
+			}
+		}
+	}
+
+	return samples, int(stream.Info.SampleRate), int(stream.Info.NChannels), nil
+}
+
+// oggVorbisDecoder decodes Ogg/Vorbis via a native Go decoder.
+type oggVorbisDecoder struct{}
+
+func (oggVorbisDecoder) Decode(r io.Reader) ([]float32, int, int, error) {
+	dec, err := oggvorbis.NewReader(r)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	buf := make([]float32, 4096)
+	var samples []float32
+	for {
+		n, err := dec.Read(buf)
+		samples = append(samples, buf[:n]...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, 0, err
+		}
+	}
+
+	return samples, dec.SampleRate(), dec.Channels(), nil
+}
+
+// downmixToMono averages interleaved multi-channel samples down to a single
+// channel. Samples already mono are returned unchanged.
+func downmixToMono(samples []float32, channels int) []float32 {
+	if channels <= 1 {
+		return samples
+	}
+
+	mono := make([]float32, len(samples)/channels)
+	for i := range mono {
+		var sum float32
+		for ch := 0; ch < channels; ch++ {
+			sum += samples[i*channels+ch]
+		}
+		mono[i] = sum / float32(channels)
+	}
+	return mono
+}
+
+// resampleLinear converts samples from srcRate to dstRate using linear
+// interpolation. It's not as accurate as a polyphase resampler, but it's
+// simple, allocation-light, and more than good enough ahead of whisper's own
+// feature extraction.
+func resampleLinear(samples []float32, srcRate, dstRate int) []float32 {
+	if srcRate <= 0 || dstRate <= 0 || srcRate == dstRate || len(samples) == 0 {
+		return samples
+	}
+
+	ratio := float64(srcRate) / float64(dstRate)
+	outLen := int(float64(len(samples)) / ratio)
+	out := make([]float32, outLen)
+	for i := range out {
+		srcPos := float64(i) * ratio
+		idx := int(srcPos)
+		frac := float32(srcPos - float64(idx))
+		if idx+1 < len(samples) {
+			out[i] = samples[idx]*(1-frac) + samples[idx+1]*frac
+		} else {
+			out[i] = samples[idx]
+		}
+	}
+	return out
+}
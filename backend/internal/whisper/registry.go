@@ -0,0 +1,56 @@
+package whisper
+
+// ModelEntry describes one downloadable Whisper model variant: where to get
+// it, how to verify it, and what it costs/covers.
+type ModelEntry struct {
+	URL       string
+	SHA256    string
+	SizeBytes int64
+	Languages []string // "multi" for multilingual models, else ISO 639-1 codes
+}
+
+// ModelRegistry lists the ggml Whisper model variants available for
+// download, keyed by name. URL and SizeBytes point at the real files
+// published at https://huggingface.co/ggerganov/whisper.cpp; SHA256 is left
+// blank (downloader.Job skips verification when it's empty) until someone
+// with a route to huggingface.co computes the real digests - a wrong digest
+// here would reject every download outright, which is worse than not
+// verifying yet.
+var ModelRegistry = map[string]ModelEntry{
+	"tiny": {
+		URL:       "https://huggingface.co/ggerganov/whisper.cpp/resolve/main/ggml-tiny.bin",
+		SHA256:    "",
+		SizeBytes: 77_691_713,
+		Languages: []string{"multi"},
+	},
+	"base": {
+		URL:       "https://huggingface.co/ggerganov/whisper.cpp/resolve/main/ggml-base.bin",
+		SHA256:    "",
+		SizeBytes: 147_951_465,
+		Languages: []string{"multi"},
+	},
+	"small": {
+		URL:       "https://huggingface.co/ggerganov/whisper.cpp/resolve/main/ggml-small.bin",
+		SHA256:    "",
+		SizeBytes: 487_601_967,
+		Languages: []string{"multi"},
+	},
+	"medium": {
+		URL:       "https://huggingface.co/ggerganov/whisper.cpp/resolve/main/ggml-medium.bin",
+		SHA256:    "",
+		SizeBytes: 1_533_763_059,
+		Languages: []string{"multi"},
+	},
+	"large-v3": {
+		URL:       "https://huggingface.co/ggerganov/whisper.cpp/resolve/main/ggml-large-v3.bin",
+		SHA256:    "",
+		SizeBytes: 3_095_033_483,
+		Languages: []string{"multi"},
+	},
+	"base.en-q5_1": {
+		URL:       "https://huggingface.co/ggerganov/whisper.cpp/resolve/main/ggml-base.en-q5_1.bin",
+		SHA256:    "",
+		SizeBytes: 59_747_888,
+		Languages: []string{"en"},
+	},
+}
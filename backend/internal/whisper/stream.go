@@ -0,0 +1,153 @@
+package whisper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+)
+
+// PartialResult is one increment of a streaming transcription: either a
+// partial guess over the current sliding window, or a Final commit once a
+// silence gap closes out a span of speech. SegmentId increases monotonically
+// so the frontend can tell which partials replace each other. StartMs/EndMs
+// are absolute offsets from the start of the stream, not relative to the
+// current window.
+type PartialResult struct {
+	SegmentId int
+	Text      string
+	IsFinal   bool
+	StartMs   int64
+	EndMs     int64
+}
+
+const (
+	streamWindowMaxSamples = 30 * 16000 // cap the rolling window at 30s of 16kHz audio
+	streamTickInterval     = 200 * time.Millisecond
+)
+
+// TranscribeStream consumes a live PCM stream (e.g. from the mic pipeline)
+// and emits partial and final transcripts as audio arrives, instead of
+// requiring a fully-buffered clip up front like TranscribeAudio. It keeps a
+// rolling window of audio, re-transcribes the window every tick while voice
+// energy exceeds Config.VoiceThreshold, and commits the window as a Final
+// result once a silence gap of Config.SilenceMs is observed. The returned
+// channel is closed when pcm closes or ctx is cancelled.
+func (s *STTService) TranscribeStream(ctx context.Context, pcm <-chan []float32) (<-chan PartialResult, error) {
+	if !s.IsReady() {
+		return nil, fmt.Errorf("Whisper STT service is not ready")
+	}
+
+	out := make(chan PartialResult)
+	go s.runStream(ctx, pcm, out)
+	return out, nil
+}
+
+func (s *STTService) runStream(ctx context.Context, pcm <-chan []float32, out chan<- PartialResult) {
+	defer close(out)
+
+	silenceMs := s.config.SilenceMs
+	if silenceMs <= 0 {
+		silenceMs = 800
+	}
+	silenceGap := time.Duration(silenceMs) * time.Millisecond
+
+	sampleRate := s.config.SampleRate
+	if sampleRate <= 0 {
+		sampleRate = 16000
+	}
+
+	var (
+		window         []float32
+		droppedSamples int64 // samples permanently removed from window's front so far, for absolute StartMs/EndMs
+		segmentId      int
+		haveVoice      bool
+		lastVoiceAt    time.Time
+	)
+
+	emit := func(final bool) {
+		if len(window) == 0 {
+			return
+		}
+
+		text, err := s.transcribeDirectly(ctx, window, s.config.Decoding)
+		if err != nil {
+			log.Printf("streaming transcription failed: %v", err)
+			return
+		}
+
+		startMs := droppedSamples * 1000 / int64(sampleRate)
+		endMs := (droppedSamples + int64(len(window))) * 1000 / int64(sampleRate)
+
+		if text != "" {
+			segmentId++
+			out <- PartialResult{
+				SegmentId: segmentId,
+				Text:      text,
+				IsFinal:   final,
+				StartMs:   startMs,
+				EndMs:     endMs,
+			}
+		}
+
+		if final {
+			droppedSamples += int64(len(window))
+			window = window[len(window):]
+		}
+	}
+
+	ticker := time.NewTicker(streamTickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			emit(true)
+			return
+
+		case samples, ok := <-pcm:
+			if !ok {
+				emit(true)
+				return
+			}
+
+			window = append(window, samples...)
+
+			if rms(samples) >= s.config.VoiceThreshold {
+				haveVoice = true
+				lastVoiceAt = time.Now()
+			} else if haveVoice && time.Since(lastVoiceAt) >= silenceGap {
+				emit(true)
+				haveVoice = false
+			}
+
+			// Keep the window bounded; drop the oldest uncommitted audio
+			// rather than let it grow unboundedly on long, quiet streams.
+			// The dropped samples still count towards droppedSamples so
+			// later StartMs/EndMs values stay absolute.
+			if len(window) > streamWindowMaxSamples {
+				drop := len(window) - streamWindowMaxSamples
+				window = window[drop:]
+				droppedSamples += int64(drop)
+			}
+
+		case <-ticker.C:
+			if haveVoice {
+				emit(false)
+			}
+		}
+	}
+}
+
+// rms returns the root-mean-square energy of a block of PCM samples.
+func rms(samples []float32) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range samples {
+		sum += float64(v) * float64(v)
+	}
+	return math.Sqrt(sum / float64(len(samples)))
+}
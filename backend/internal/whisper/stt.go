@@ -1,7 +1,11 @@
 package whisper
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -10,11 +14,12 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
-	"archive/zip"
 
+	"alice-backend/internal/downloader"
 	"alice-backend/internal/embedded"
 )
 
@@ -24,6 +29,39 @@ type Config struct {
 	ModelPath      string
 	SampleRate     int
 	VoiceThreshold float64
+
+	// Backend selects the transcription engine: "binary" (default) shells
+	// out to the whisper-cli executable per call, "cgo" loads the model
+	// once in-process via the official whisper.cpp Go bindings. The cgo
+	// backend requires building with -tags whisper_cgo and a working
+	// libwhisper; Initialize returns an error if it's unavailable.
+	Backend string
+
+	// SilenceMs is the gap of low-energy audio that TranscribeStream treats
+	// as the end of an utterance, committing the buffered window as a
+	// Final result. Defaults to 800ms.
+	SilenceMs int
+
+	// PreferAccelerator overrides automatic hardware detection when
+	// downloading the whisper.cpp binary: "cublas", "vulkan", "coreml", or
+	// "cpu". Leave empty (or "auto") to detect the best available backend.
+	PreferAccelerator string
+
+	// Decoding holds the default FullParams-style tuning applied to every
+	// transcription. Callers that need a per-request override (e.g. an
+	// initial_prompt biased toward the current conversation) should use
+	// TranscribeAudioWithOptions instead of mutating this.
+	Decoding DecodingOptions
+
+	// AssetMirrorURL, if set, is passed to the STTService's AssetManager as
+	// an HTTP fallback for whisper archives missing from both the embedded
+	// assets and the local cache. See embedded.AssetManager.SetAssetMirror.
+	AssetMirrorURL string
+
+	// ProgressReporter, if set, is passed to the STTService's AssetManager
+	// so download/extract/verify progress during Initialize's EnsureAssets
+	// call is reported to it. See embedded.AssetManager.SetProgressReporter.
+	ProgressReporter embedded.ProgressReporter
 }
 
 // ServiceInfo contains information about the STT service
@@ -44,6 +82,8 @@ type STTService struct {
 	config       *Config
 	info         *ServiceInfo
 	assetManager *embedded.AssetManager
+	cgoEngine    *cgoEngine // non-nil once Config.Backend == "cgo" has been loaded
+	downloads    *downloader.Manager
 }
 
 // NewSTTService creates a new STT service
@@ -56,10 +96,13 @@ func NewSTTService(config *Config) *STTService {
 	}
 
 	assetManager := embedded.NewAssetManager(".")
+	assetManager.SetAssetMirror(config.AssetMirrorURL)
+	assetManager.SetProgressReporter(config.ProgressReporter)
 
 	return &STTService{
 		config:       config,
 		assetManager: assetManager,
+		downloads:    downloader.NewManager(),
 		info: &ServiceInfo{
 			Name:        "Whisper STT",
 			Version:     "1.0.0",
@@ -82,6 +125,20 @@ func (s *STTService) Initialize(ctx context.Context) error {
 	if s.config.ModelPath == "" {
 		s.config.ModelPath = "models/whisper-base.bin"
 	}
+	if s.config.Backend == "" {
+		s.config.Backend = "binary"
+	}
+
+	if s.config.Backend == "cgo" {
+		engine, err := newCgoEngine(s.config.ModelPath)
+		if err != nil {
+			return fmt.Errorf("failed to initialize cgo whisper engine: %w", err)
+		}
+		s.cgoEngine = engine
+		s.info.Metadata["backend"] = "cgo"
+	} else {
+		s.info.Metadata["backend"] = "binary"
+	}
 
 	s.ready = true
 	s.info.Status = "ready"
@@ -108,32 +165,116 @@ func (s *STTService) GetInfo() *ServiceInfo {
 	return &info
 }
 
-// TranscribeAudio performs actual speech transcription using Python whisper
+// TranscribeAudio performs speech transcription and returns the flattened text.
 func (s *STTService) TranscribeAudio(ctx context.Context, audioData []byte) (string, error) {
+	result, err := s.TranscribeAudioDetailed(ctx, audioData)
+	if err != nil {
+		return "", err
+	}
+	return result.Text, nil
+}
+
+// TranscribeAudioDetailed performs speech transcription and returns the full
+// TranscribeResult, including per-segment timing and token detail when the
+// active backend can produce it (currently only the cgo backend).
+func (s *STTService) TranscribeAudioDetailed(ctx context.Context, audioData []byte) (*TranscribeResult, error) {
+	return s.TranscribeAudioWithOptions(ctx, audioData, "")
+}
+
+// TranscribeAudioWithOptions performs speech transcription like
+// TranscribeAudioDetailed, but lets the caller override Config.Decoding's
+// initial prompt for this call only. Biasing the prompt toward domain
+// vocabulary (names, jargon) on a per-request basis is the standard way to
+// improve whisper accuracy in assistant contexts, without mutating the
+// service's shared decoding defaults.
+func (s *STTService) TranscribeAudioWithOptions(ctx context.Context, audioData []byte, initialPrompt string) (*TranscribeResult, error) {
+	return s.TranscribeAudioWithDecoding(ctx, audioData, DecodingOptions{InitialPrompt: initialPrompt})
+}
+
+// TranscribeAudioWithDecoding performs speech transcription like
+// TranscribeAudioDetailed, but overrides Config.Decoding for this call only
+// with any non-zero fields set on overrides. Used by callers (e.g. the
+// OpenAI-compatible endpoint) that need to honor per-request decoding
+// parameters without touching the service's shared defaults.
+func (s *STTService) TranscribeAudioWithDecoding(ctx context.Context, audioData []byte, overrides DecodingOptions) (*TranscribeResult, error) {
 	if !s.IsReady() {
-		return "", fmt.Errorf("Whisper STT service is not ready")
+		return nil, fmt.Errorf("Whisper STT service is not ready")
 	}
 
 	if len(audioData) == 0 {
-		return "", fmt.Errorf("audio data cannot be empty")
+		return nil, fmt.Errorf("audio data cannot be empty")
 	}
 
-	samples, err := s.convertAudioToSamples(audioData)
+	samples, err := s.decodeAudio(ctx, audioData)
 	if err != nil {
-		return "", fmt.Errorf("failed to convert audio: %w", err)
+		return nil, fmt.Errorf("failed to decode audio: %w", err)
 	}
 
 	if len(samples) == 0 {
-		return "", nil
+		return &TranscribeResult{}, nil
+	}
+
+	decoding := mergeDecodingOptions(s.config.Decoding, overrides)
+
+	s.mu.RLock()
+	engine := s.cgoEngine
+	s.mu.RUnlock()
+
+	if engine != nil {
+		result, err := engine.transcribe(ctx, samples, s.config, decoding)
+		if err != nil {
+			log.Printf("cgo transcription failed: %v", err)
+			return nil, fmt.Errorf("transcription failed: %w", err)
+		}
+		return result, nil
 	}
 
-	text, err := s.transcribeDirectly(ctx, samples)
+	text, err := s.transcribeDirectly(ctx, samples, decoding)
 	if err != nil {
 		log.Printf("Transcription failed: %v", err)
-		return "", fmt.Errorf("transcription failed: %w", err)
+		return nil, fmt.Errorf("transcription failed: %w", err)
 	}
 
-	return text, nil
+	return &TranscribeResult{Text: text}, nil
+}
+
+// decodeAudio converts an arbitrary audio payload into 16kHz mono float32
+// samples for whisper. It sniffs the payload for a known container
+// (WAV/MP3/FLAC/Ogg) and decodes it natively, resampling and downmixing the
+// result to whisper's expected format. Anything it doesn't recognize
+// (WebM/Opus, M4A/AAC, ...) is handed to ffmpeg; only if ffmpeg itself isn't
+// installed does it fall back to the legacy assumption that audioData is
+// already raw 16kHz mono PCM16, which is what every pre-existing caller
+// (frontend capture, streaming) sends.
+func (s *STTService) decodeAudio(ctx context.Context, audioData []byte) ([]float32, error) {
+	var decoder AudioDecoder
+	switch detectAudioFormat(audioData) {
+	case "wav":
+		decoder = wavDecoder{}
+	case "mp3":
+		decoder = mp3Decoder{}
+	case "flac":
+		decoder = flacDecoder{}
+	case "ogg":
+		decoder = oggVorbisDecoder{}
+	}
+
+	if decoder != nil {
+		samples, sampleRate, channels, err := decoder.Decode(bytes.NewReader(audioData))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode audio: %w", err)
+		}
+		return resampleLinear(downmixToMono(samples, channels), sampleRate, 16000), nil
+	}
+
+	pcm, err := s.DecodeToPCM16(ctx, audioData)
+	if err == nil {
+		return s.convertAudioToSamples(pcm)
+	}
+	if errors.Is(err, errFfmpegNotFound) {
+		return s.convertAudioToSamples(audioData)
+	}
+	return nil, err
 }
 
 // convertAudioToSamples converts byte audio data to float32 samples
@@ -153,6 +294,43 @@ func (s *STTService) convertAudioToSamples(audioData []byte) ([]float32, error)
 	return samples, nil
 }
 
+// errFfmpegNotFound distinguishes "ffmpeg isn't installed" from "ffmpeg
+// rejected this file", so decodeAudio can fall back to the legacy
+// raw-PCM assumption only in the former case instead of masking real
+// decode failures as silent garbage transcripts.
+var errFfmpegNotFound = errors.New("ffmpeg not found in PATH")
+
+// DecodeToPCM16 shells out to ffmpeg to transcode an arbitrary audio
+// container (WebM/Opus, M4A/AAC, ...) into raw 16kHz mono signed 16-bit
+// PCM, the format convertAudioToSamples expects. It's the last-resort
+// fallback in decodeAudio for containers none of the native Go decoders
+// in decoder.go recognize.
+func (s *STTService) DecodeToPCM16(ctx context.Context, audioData []byte) ([]byte, error) {
+	ffmpegPath, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errFfmpegNotFound, err)
+	}
+
+	cmd := exec.CommandContext(ctx, ffmpegPath,
+		"-i", "pipe:0",
+		"-f", "s16le",
+		"-ar", "16000",
+		"-ac", "1",
+		"pipe:1",
+	)
+	cmd.Stdin = bytes.NewReader(audioData)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg decode failed: %w (%s)", err, stderr.String())
+	}
+
+	return stdout.Bytes(), nil
+}
+
 // writeWAVFile writes float32 samples to a WAV file
 func (s *STTService) writeWAVFile(filename string, samples []float32) error {
 	const sampleRate = 16000
@@ -212,8 +390,104 @@ func (s *STTService) writeWAVFile(filename string, samples []float32) error {
 	return nil
 }
 
+// mergeDecodingOptions layers non-zero fields from override on top of base,
+// leaving base's value wherever override left a field at its zero value.
+func mergeDecodingOptions(base, override DecodingOptions) DecodingOptions {
+	merged := base
+
+	if override.BeamSize > 0 {
+		merged.BeamSize = override.BeamSize
+	}
+	if override.BestOf > 0 {
+		merged.BestOf = override.BestOf
+	}
+	if override.Temperature > 0 {
+		merged.Temperature = override.Temperature
+	}
+	if override.TemperatureIncrement > 0 {
+		merged.TemperatureIncrement = override.TemperatureIncrement
+	}
+	if override.DisableTemperatureFallback {
+		merged.DisableTemperatureFallback = true
+	}
+	if override.NoSpeechThreshold > 0 {
+		merged.NoSpeechThreshold = override.NoSpeechThreshold
+	}
+	if override.LogProbThreshold != 0 {
+		merged.LogProbThreshold = override.LogProbThreshold
+	}
+	if override.EntropyThreshold > 0 {
+		merged.EntropyThreshold = override.EntropyThreshold
+	}
+	if override.SuppressBlank {
+		merged.SuppressBlank = true
+	}
+	if override.InitialPrompt != "" {
+		merged.InitialPrompt = override.InitialPrompt
+	}
+	if override.Translate {
+		merged.Translate = true
+	}
+	if override.WordTimestamps {
+		merged.WordTimestamps = true
+	}
+	if override.SpeakerTurnDetection {
+		merged.SpeakerTurnDetection = true
+	}
+
+	return merged
+}
+
+// decodingArgs translates DecodingOptions into whisper-cli flags.
+// SuppressBlank and WordTimestamps' real word-level output have no direct
+// whisper-cli equivalent, so WordTimestamps is approximated with "-ml 1" and
+// SuppressBlank is silently ignored on this backend (the cgo backend doesn't
+// apply it either).
+func decodingArgs(opts DecodingOptions) []string {
+	var args []string
+
+	if opts.BeamSize > 0 {
+		args = append(args, "-bs", strconv.Itoa(opts.BeamSize))
+	}
+	if opts.BestOf > 0 {
+		args = append(args, "-bo", strconv.Itoa(opts.BestOf))
+	}
+	if opts.Temperature > 0 {
+		args = append(args, "-tp", strconv.FormatFloat(opts.Temperature, 'f', -1, 64))
+	}
+	if opts.TemperatureIncrement > 0 {
+		args = append(args, "-tpi", strconv.FormatFloat(opts.TemperatureIncrement, 'f', -1, 64))
+	}
+	if opts.DisableTemperatureFallback {
+		args = append(args, "-nf")
+	}
+	if opts.NoSpeechThreshold > 0 {
+		args = append(args, "-nth", strconv.FormatFloat(opts.NoSpeechThreshold, 'f', -1, 64))
+	}
+	if opts.LogProbThreshold != 0 {
+		args = append(args, "-lpt", strconv.FormatFloat(opts.LogProbThreshold, 'f', -1, 64))
+	}
+	if opts.EntropyThreshold > 0 {
+		args = append(args, "-et", strconv.FormatFloat(opts.EntropyThreshold, 'f', -1, 64))
+	}
+	if opts.InitialPrompt != "" {
+		args = append(args, "--prompt", opts.InitialPrompt)
+	}
+	if opts.Translate {
+		args = append(args, "-tr")
+	}
+	if opts.WordTimestamps {
+		args = append(args, "-ml", "1")
+	}
+	if opts.SpeakerTurnDetection {
+		args = append(args, "-tdrz")
+	}
+
+	return args
+}
+
 // transcribeDirectly performs direct transcription using whisper.cpp binary
-func (s *STTService) transcribeDirectly(ctx context.Context, samples []float32) (string, error) {
+func (s *STTService) transcribeDirectly(ctx context.Context, samples []float32, decoding DecodingOptions) (string, error) {
 	log.Printf("Direct transcription: processing %d audio samples", len(samples))
 	
 	if len(samples) == 0 {
@@ -264,7 +538,8 @@ func (s *STTService) transcribeDirectly(ctx context.Context, samples []float32)
 	if s.config.Language != "" && s.config.Language != "auto" {
 		args = append(args, "-l", s.config.Language)
 	}
-	
+	args = append(args, decodingArgs(decoding)...)
+
 	log.Printf("Executing whisper: %s %v", whisperPath, args)
 	
 	cmd := exec.CommandContext(ctx, whisperPath, args...)
@@ -298,44 +573,164 @@ func (s *STTService) transcribeDirectly(ctx context.Context, samples []float32)
 	return text, nil
 }
 
-// downloadWhisperBinary downloads the whisper.cpp binary for the current platform
+// whisperReleasesURL is the GitHub API endpoint for the latest whisper.cpp
+// release, used to resolve a download asset instead of pinning a version.
+const whisperReleasesURL = "https://api.github.com/repos/ggerganov/whisper.cpp/releases/latest"
+
+// githubRelease is the subset of the GitHub releases API response needed to
+// pick a release asset by name.
+type githubRelease struct {
+	TagName string `json:"tag_name"`
+	Assets  []struct {
+		Name               string `json:"name"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// detectAccelerator probes the host for a supported whisper.cpp hardware
+// backend, returning "cublas", "vulkan", "coreml", or "cpu". An explicit
+// Config.PreferAccelerator (other than "" or "auto") always wins.
+func detectAccelerator(prefer string) string {
+	if prefer != "" && prefer != "auto" {
+		return prefer
+	}
+
+	if runtime.GOOS == "darwin" && runtime.GOARCH == "arm64" {
+		return "coreml"
+	}
+
+	if _, err := exec.LookPath("nvidia-smi"); err == nil {
+		return "cublas"
+	}
+	for _, libPath := range []string{"/usr/lib/x86_64-linux-gnu/libcudart.so", "/usr/local/cuda/lib64/libcudart.so"} {
+		if _, err := os.Stat(libPath); err == nil {
+			return "cublas"
+		}
+	}
+
+	if _, err := exec.LookPath("vulkaninfo"); err == nil {
+		return "vulkan"
+	}
+
+	return "cpu"
+}
+
+// acceleratorAssetTokens returns the release-asset filename substrings that
+// identify a build for the given accelerator.
+func acceleratorAssetTokens(accelerator string) []string {
+	switch accelerator {
+	case "cublas":
+		return []string{"cublas", "cuda"}
+	case "vulkan":
+		return []string{"vulkan"}
+	case "coreml":
+		return []string{"coreml"}
+	default:
+		return nil
+	}
+}
+
+var allAcceleratorTokens = []string{"cublas", "cuda", "vulkan", "coreml"}
+
+func containsAny(name string, tokens []string) bool {
+	for _, token := range tokens {
+		if strings.Contains(name, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveWhisperAssetURL queries the GitHub releases API for the latest
+// whisper.cpp release and picks the ZIP asset matching platform/arch/
+// accelerator, falling back to a plain CPU build if no accelerated asset is
+// published for this release.
+func resolveWhisperAssetURL(ctx context.Context, platform, arch, accelerator string) (string, error) {
+	osTokens := map[string][]string{
+		"windows": {"win"},
+		"darwin":  {"macos", "darwin"},
+		"linux":   {"linux"},
+	}[platform]
+	archTokens := map[string][]string{
+		"amd64": {"x64", "x86_64"},
+		"arm64": {"arm64", "aarch64"},
+	}[arch]
+	if osTokens == nil || archTokens == nil {
+		return "", fmt.Errorf("unsupported platform: %s/%s", platform, arch)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, whisperReleasesURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to query GitHub releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GitHub releases API returned status: %d", resp.StatusCode)
+	}
+
+	var release githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", fmt.Errorf("failed to parse GitHub release response: %w", err)
+	}
+
+	acceleratorTokens := acceleratorAssetTokens(accelerator)
+
+	var cpuFallback string
+	for _, asset := range release.Assets {
+		name := strings.ToLower(asset.Name)
+		if !strings.HasSuffix(name, ".zip") || !containsAny(name, osTokens) || !containsAny(name, archTokens) {
+			continue
+		}
+		if len(acceleratorTokens) > 0 && containsAny(name, acceleratorTokens) {
+			return asset.BrowserDownloadURL, nil
+		}
+		if !containsAny(name, allAcceleratorTokens) && cpuFallback == "" {
+			cpuFallback = asset.BrowserDownloadURL
+		}
+	}
+
+	if cpuFallback != "" {
+		return cpuFallback, nil
+	}
+
+	return "", fmt.Errorf("no matching whisper.cpp release asset found for %s/%s (%s)", platform, arch, release.TagName)
+}
+
+// downloadWhisperBinary downloads the whisper.cpp binary for the current
+// platform, preferring a hardware-accelerated build when one is available.
 func (s *STTService) downloadWhisperBinary(ctx context.Context) error {
 	platform := runtime.GOOS
 	arch := runtime.GOARCH
-	
-	var downloadURL string
+
 	var binaryName string
-	
 	switch platform {
 	case "windows":
 		binaryName = "whisper-cli.exe"
-		if arch == "amd64" {
-			downloadURL = "https://github.com/ggerganov/whisper.cpp/releases/download/v1.5.4/whisper-1.5.4-win-x64.zip"
-		} else {
-			return fmt.Errorf("unsupported Windows architecture: %s", arch)
-		}
-	case "darwin":
-		binaryName = "whisper-cli"
-		if arch == "amd64" {
-			downloadURL = "https://github.com/ggerganov/whisper.cpp/releases/download/v1.5.4/whisper-1.5.4-macos-x64.zip"
-		} else if arch == "arm64" {
-			downloadURL = "https://github.com/ggerganov/whisper.cpp/releases/download/v1.5.4/whisper-1.5.4-macos-arm64.zip"
-		} else {
-			return fmt.Errorf("unsupported macOS architecture: %s", arch)
-		}
-	case "linux":
+	case "darwin", "linux":
 		binaryName = "whisper-cli"
-		if arch == "amd64" {
-			downloadURL = "https://github.com/ggerganov/whisper.cpp/releases/download/v1.5.4/whisper-1.5.4-linux-x64.zip"
-		} else {
-			return fmt.Errorf("unsupported Linux architecture: %s", arch)
-		}
 	default:
 		return fmt.Errorf("unsupported platform: %s", platform)
 	}
-	
-	log.Printf("Downloading whisper binary for %s/%s from %s", platform, arch, downloadURL)
-	
+
+	accelerator := detectAccelerator(s.config.PreferAccelerator)
+	s.mu.Lock()
+	s.info.Metadata["accelerator"] = accelerator
+	s.mu.Unlock()
+
+	downloadURL, err := resolveWhisperAssetURL(ctx, platform, arch, accelerator)
+	if err != nil {
+		return fmt.Errorf("failed to resolve whisper release asset: %w", err)
+	}
+
+	log.Printf("Downloading whisper binary for %s/%s (%s) from %s", platform, arch, accelerator, downloadURL)
+
 	binDir := filepath.Dir(s.assetManager.GetBinaryPath("whisper"))
 	if err := os.MkdirAll(binDir, 0755); err != nil {
 		return fmt.Errorf("failed to create bin directory: %w", err)
@@ -465,11 +860,78 @@ func (s *STTService) downloadWhisperModel(ctx context.Context, modelPath string)
 	return nil
 }
 
+// DownloadModel launches (or returns the already-running) background
+// download job for a ModelRegistry entry. The job streams to a .part file,
+// verifies its SHA-256 digest when the entry lists one (verification is
+// skipped for a blank SHA256), and then renames it into modelPathFor.
+func (s *STTService) DownloadModel(ctx context.Context, name string) (*downloader.Job, error) {
+	entry, ok := ModelRegistry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown whisper model: %s", name)
+	}
+
+	return s.downloads.Start(name, entry.URL, modelPathFor(name), entry.SHA256), nil
+}
+
+// ModelDownloadStatus returns the tracked download job for a model, if one
+// has been started.
+func (s *STTService) ModelDownloadStatus(name string) (*downloader.Job, bool) {
+	return s.downloads.Status(name)
+}
+
+// SelectModel switches the active model to a previously downloaded
+// ModelRegistry entry without restarting the service. With the cgo backend
+// the engine is reloaded in place; with the binary backend the new path is
+// simply used on the next transcription.
+func (s *STTService) SelectModel(name string) error {
+	if _, ok := ModelRegistry[name]; !ok {
+		return fmt.Errorf("unknown whisper model: %s", name)
+	}
+
+	modelPath := modelPathFor(name)
+	if _, err := os.Stat(modelPath); err != nil {
+		return fmt.Errorf("model %s has not been downloaded: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cgoEngine != nil {
+		engine, err := newCgoEngine(modelPath)
+		if err != nil {
+			return fmt.Errorf("failed to load model %s: %w", name, err)
+		}
+		if err := s.cgoEngine.Close(); err != nil {
+			log.Printf("Warning: failed to close previous cgo whisper engine: %v", err)
+		}
+		s.cgoEngine = engine
+	}
+
+	s.config.ModelPath = modelPath
+	s.info.Model = name
+	s.info.LastUpdated = time.Now()
+
+	log.Printf("Whisper active model switched to: %s", name)
+	return nil
+}
+
+// modelPathFor returns the on-disk destination for a ModelRegistry entry.
+func modelPathFor(name string) string {
+	return filepath.Join("models", fmt.Sprintf("whisper-%s.bin", name))
+}
+
 // Shutdown gracefully shuts down the STT service
 func (s *STTService) Shutdown(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.cgoEngine != nil {
+		if err := s.cgoEngine.Close(); err != nil {
+			log.Printf("Warning: failed to close cgo whisper engine: %v", err)
+		}
+		s.cgoEngine = nil
+	}
+
 	s.ready = false
 	s.info.Status = "stopped"
 	s.info.LastUpdated = time.Now()
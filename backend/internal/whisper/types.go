@@ -0,0 +1,54 @@
+package whisper
+
+import "time"
+
+// Token is a single decoded token with its timing and confidence.
+type Token struct {
+	Id    int
+	Text  string
+	P     float32
+	Start time.Duration
+	End   time.Duration
+}
+
+// Segment is a contiguous span of transcribed speech.
+type Segment struct {
+	Text   string
+	Start  time.Duration
+	End    time.Duration
+	Tokens []Token
+}
+
+// TranscribeResult is the structured output of a transcription. Text is the
+// flattened transcript (what TranscribeAudio returns); Segments carries the
+// per-segment timing and token detail that the binary+exec path can't
+// produce but the cgo backend can.
+type TranscribeResult struct {
+	Text     string
+	Segments []Segment
+}
+
+// DecodingOptions tunes whisper.cpp's decode behavior beyond the language
+// selector. Zero values mean "use whisper.cpp's own default" for that field.
+// The binary backend maps these onto whisper-cli flags in decodingArgs; the
+// cgo backend maps the subset the official Go bindings expose (BeamSize,
+// Temperature, TemperatureIncrement, EntropyThreshold, InitialPrompt,
+// Translate, WordTimestamps) via applyDecodingOptions. BestOf,
+// NoSpeechThreshold, DisableTemperatureFallback, and SpeakerTurnDetection
+// currently have no equivalent in the cgo bindings and only take effect on
+// the binary backend. SuppressBlank is applied by neither backend.
+type DecodingOptions struct {
+	BeamSize                   int
+	BestOf                     int
+	Temperature                float64
+	TemperatureIncrement       float64 // step size when falling back from a failed decode (whisper-cli -tpi)
+	DisableTemperatureFallback bool    // force a single decode at Temperature (whisper-cli -nf)
+	NoSpeechThreshold          float64
+	LogProbThreshold           float64
+	EntropyThreshold           float64
+	SuppressBlank              bool
+	InitialPrompt              string
+	Translate                  bool // translate to English instead of transcribing in the source language
+	WordTimestamps             bool
+	SpeakerTurnDetection       bool // tinydiarize speaker-turn detection (whisper-cli -tdrz)
+}
@@ -2,8 +2,11 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"net"
 	"os"
+	"os/exec"
 	"os/signal"
 	"syscall"
 	"time"
@@ -14,13 +17,33 @@ import (
 	"alice-backend/internal/server"
 )
 
+// listenFDEnv, when set in a child's environment, names the file descriptor
+// number (inherited via exec.Cmd.ExtraFiles) of a listening socket handed
+// down by a parent process during a SIGUSR2 live-reload handoff (see
+// handleUSR2 below). Its presence tells the child to take over that socket
+// with server.Serve instead of binding a fresh one with server.Start.
+const listenFDEnv = "ALICE_LISTEN_FD"
+
 func main() {
+	// Structured (JSON) logs everywhere, so every slog.Info/Warn/Error call
+	// across the codebase - including the per-request logs from
+	// server.structuredLoggingMiddleware - comes out machine-parseable
+	// instead of slog's default human-readable text format.
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
 	// Load configuration
 	cfg := config.LoadConfig()
 
 	// Initialize model manager
 	modelManager := models.NewManager(cfg)
 
+	// The download-events hub doubles as modelManager's asset progress
+	// reporter, so it has to exist (and be installed) before Initialize
+	// runs - otherwise a client subscribed to GET /api/models/download-events
+	// would miss the startup EnsureAssets run entirely.
+	downloadEvents := api.NewDownloadEventHub()
+	modelManager.SetProgressReporter(downloadEvents)
+
 	// Initialize services
 	ctx := context.Background()
 	if err := modelManager.Initialize(ctx); err != nil {
@@ -29,24 +52,43 @@ func main() {
 	}
 
 	// Create API handler
-	apiHandler := api.NewHandler(cfg, modelManager)
+	apiHandler := api.NewHandler(cfg, modelManager, downloadEvents)
 
 	// Create server
 	srv := server.NewServer(cfg, apiHandler)
 
+	listener, err := acquireListener(cfg.Server.Port)
+	if err != nil {
+		slog.Error("Failed to acquire listener", "error", err)
+		os.Exit(1)
+	}
+
 	// Start server in a goroutine
 	go func() {
-		slog.Info("Starting HTTP server", "address", ":"+cfg.Server.Port)
-		if err := srv.Start(cfg.Server.Port); err != nil {
+		slog.Info("Starting HTTP server", "address", listener.Addr().String())
+		if err := srv.Serve(listener); err != nil && err != net.ErrClosed {
 			slog.Error("Server error", "error", err)
 			os.Exit(1)
 		}
 	}()
 
-	// Wait for interrupt signal
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	// Wait for a signal: SIGHUP reloads configuration in place, SIGUSR2
+	// forks a replacement process and hands it the listening socket for a
+	// zero-downtime binary upgrade, SIGINT/SIGTERM shut down gracefully.
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGUSR2)
+
+	for sig := range sigs {
+		switch sig {
+		case syscall.SIGHUP:
+			handleReload(ctx, modelManager)
+			continue
+		case syscall.SIGUSR2:
+			handleUSR2(listener)
+			continue
+		}
+		break
+	}
 
 	slog.Info("Shutting down server...")
 
@@ -66,3 +108,91 @@ func main() {
 
 	slog.Info("Server stopped")
 }
+
+// acquireListener binds a new TCP listener on port, unless this process was
+// exec'd by a parent handing off a live socket (see handleUSR2), in which
+// case it reconstructs a net.Listener from the inherited file descriptor
+// instead so both processes can serve the same port during the handoff
+// window.
+func acquireListener(port string) (net.Listener, error) {
+	if fdStr := os.Getenv(listenFDEnv); fdStr != "" {
+		var fd int
+		if _, err := fmt.Sscanf(fdStr, "%d", &fd); err != nil {
+			return nil, fmt.Errorf("invalid %s=%q: %w", listenFDEnv, fdStr, err)
+		}
+		file := os.NewFile(uintptr(fd), "alice-listener")
+		listener, err := net.FileListener(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inherit listener fd %d: %w", fd, err)
+		}
+		file.Close()
+		slog.Info("Inherited listening socket from parent process", "fd", fd)
+		return listener, nil
+	}
+
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on port %s: %w", port, err)
+	}
+	return listener, nil
+}
+
+// handleReload re-reads configuration from the environment and asks the
+// model manager to selectively re-initialize whichever services changed,
+// without restarting the process or dropping the listening socket.
+func handleReload(ctx context.Context, modelManager *models.Manager) {
+	slog.Info("Received SIGHUP, reloading configuration")
+	newCfg := config.LoadConfig()
+	if err := modelManager.Reload(ctx, newCfg); err != nil {
+		slog.Error("Config reload failed", "error", err)
+		return
+	}
+	slog.Info("Configuration reloaded")
+}
+
+// handleUSR2 forks a copy of the running binary, handing it the listening
+// socket's file descriptor via ExtraFiles so it can call acquireListener and
+// start serving the same port immediately. The old process keeps running
+// and serving in-flight and new connections until it receives SIGTERM -
+// there is no automatic self-termination, so an operator (or deploy script)
+// drives the handoff by sending SIGUSR2 followed by SIGTERM once the new
+// process reports healthy.
+func handleUSR2(listener net.Listener) {
+	tcpListener, ok := listener.(*net.TCPListener)
+	if !ok {
+		slog.Error("SIGUSR2 handoff requires a TCP listener", "type", fmt.Sprintf("%T", listener))
+		return
+	}
+
+	listenerFile, err := tcpListener.File()
+	if err != nil {
+		slog.Error("Failed to dup listener for handoff", "error", err)
+		return
+	}
+	defer listenerFile.Close()
+
+	execPath, err := os.Executable()
+	if err != nil {
+		slog.Error("Failed to resolve executable for handoff", "error", err)
+		return
+	}
+
+	// The dup'd listener fd becomes fd 3 in the child: stdin/stdout/stderr
+	// occupy 0-2, and ExtraFiles[0] is always assigned the next descriptor.
+	const inheritedFD = 3
+	env := append(os.Environ(), fmt.Sprintf("%s=%d", listenFDEnv, inheritedFD))
+
+	cmd := exec.Command(execPath, os.Args[1:]...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = []*os.File{listenerFile}
+
+	if err := cmd.Start(); err != nil {
+		slog.Error("Failed to start replacement process", "error", err)
+		return
+	}
+
+	slog.Info("Handed off listening socket to replacement process", "pid", cmd.Process.Pid)
+}
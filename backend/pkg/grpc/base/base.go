@@ -0,0 +1,95 @@
+// Package base is a net/rpc worker shim, not real gRPC, despite its import
+// path: proto/backend.proto describes the RPC surface (Health, LoadModel,
+// Predict, Embed, TranscribeAudio, SynthesizeSpeech) this package actually
+// implements over net/rpc, because google.golang.org/grpc and its
+// protoc/protobuf codegen toolchain aren't vendored in this module and this
+// build environment has no network access to fetch them and regenerate
+// go.sum. It provides the request/response types below, a Server/Client
+// pair, and a SupervisedWorker that models.Manager uses to run an adapted
+// whisper/piper/minilm service as a supervised subprocess. net/rpc gives
+// the same out-of-process-worker-over-a-socket shape proto/backend.proto
+// describes - a crash in a worker doesn't take the API server down with it
+// - so callers get that benefit today; swapping the transport for real
+// gRPC later shouldn't require changing the Backend interface below.
+package base
+
+// HealthRequest is the argument to Backend.Health; it carries no fields,
+// matching proto/backend.proto's empty HealthRequest message.
+type HealthRequest struct{}
+
+// HealthResponse reports whether a worker has finished loading its model.
+type HealthResponse struct {
+	Ready  bool
+	Status string
+}
+
+// LoadModelRequest asks a worker to (re)load a model from ModelPath.
+type LoadModelRequest struct {
+	ModelPath string
+	Params    map[string]string
+}
+
+// LoadModelResponse carries no fields; present for net/rpc's argument shape
+// and parity with proto/backend.proto's LoadModelResponse message.
+type LoadModelResponse struct{}
+
+// PredictRequest is a generic text-in request for workers that don't fit
+// Embed/TranscribeAudio/SynthesizeSpeech's more specific shapes.
+type PredictRequest struct {
+	Input string
+}
+
+// PredictResponse is PredictRequest's text-out result.
+type PredictResponse struct {
+	Output string
+}
+
+// EmbedRequest asks for one embedding vector per entry in Texts.
+type EmbedRequest struct {
+	Texts []string
+}
+
+// EmbedResponse returns one vector per EmbedRequest.Texts entry, in order.
+type EmbedResponse struct {
+	Vectors [][]float32
+}
+
+// TranscribeRequest carries a raw PCM audio buffer to transcribe.
+type TranscribeRequest struct {
+	Audio      []byte
+	SampleRate int
+	Language   string
+}
+
+// TranscribeResponse is TranscribeRequest's text transcription.
+type TranscribeResponse struct {
+	Text string
+}
+
+// SynthesizeRequest asks for WAV audio of Text spoken in Voice.
+type SynthesizeRequest struct {
+	Text  string
+	Voice string
+	Speed float32
+}
+
+// SynthesizeResponse carries WAV-encoded synthesized audio.
+type SynthesizeResponse struct {
+	Audio []byte
+}
+
+// Backend is the method set a worker registers for net/rpc to dispatch
+// against, matching proto/backend.proto's service exactly. A worker that
+// doesn't implement one of these (e.g. an embeddings-only backend has no
+// sensible TranscribeAudio) should still implement the method and return a
+// "not supported by this backend" error, rather than omitting it - net/rpc
+// requires every method Backend declares to be present on the registered
+// receiver.
+type Backend interface {
+	Health(req HealthRequest, resp *HealthResponse) error
+	LoadModel(req LoadModelRequest, resp *LoadModelResponse) error
+	Predict(req PredictRequest, resp *PredictResponse) error
+	Embed(req EmbedRequest, resp *EmbedResponse) error
+	TranscribeAudio(req TranscribeRequest, resp *TranscribeResponse) error
+	SynthesizeSpeech(req SynthesizeRequest, resp *SynthesizeResponse) error
+}
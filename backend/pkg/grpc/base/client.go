@@ -0,0 +1,87 @@
+package base
+
+import (
+	"fmt"
+	"net/rpc"
+)
+
+// Client is the manager-side handle to a worker's Server, offering one
+// method per Backend RPC instead of making callers spell out
+// rpc.Client.Call's "Backend.Method" strings themselves.
+type Client struct {
+	rpcClient *rpc.Client
+}
+
+// Dial connects to a Server listening on network/address. Callers
+// launching a subprocess worker should prefer DialWithRetry, since the
+// worker needs time to start listening after being spawned.
+func Dial(network, address string) (*Client, error) {
+	conn, err := rpc.Dial(network, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial backend at %s %s: %w", network, address, err)
+	}
+	return &Client{rpcClient: conn}, nil
+}
+
+func (c *Client) call(method string, args, reply interface{}) error {
+	if err := c.rpcClient.Call(backendServiceName+"."+method, args, reply); err != nil {
+		return fmt.Errorf("backend %s failed: %w", method, err)
+	}
+	return nil
+}
+
+// Health calls the worker's Health RPC.
+func (c *Client) Health() (*HealthResponse, error) {
+	var resp HealthResponse
+	if err := c.call("Health", HealthRequest{}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// LoadModel calls the worker's LoadModel RPC.
+func (c *Client) LoadModel(req LoadModelRequest) error {
+	var resp LoadModelResponse
+	return c.call("LoadModel", req, &resp)
+}
+
+// Predict calls the worker's Predict RPC.
+func (c *Client) Predict(req PredictRequest) (*PredictResponse, error) {
+	var resp PredictResponse
+	if err := c.call("Predict", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Embed calls the worker's Embed RPC.
+func (c *Client) Embed(req EmbedRequest) (*EmbedResponse, error) {
+	var resp EmbedResponse
+	if err := c.call("Embed", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// TranscribeAudio calls the worker's TranscribeAudio RPC.
+func (c *Client) TranscribeAudio(req TranscribeRequest) (*TranscribeResponse, error) {
+	var resp TranscribeResponse
+	if err := c.call("TranscribeAudio", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SynthesizeSpeech calls the worker's SynthesizeSpeech RPC.
+func (c *Client) SynthesizeSpeech(req SynthesizeRequest) (*SynthesizeResponse, error) {
+	var resp SynthesizeResponse
+	if err := c.call("SynthesizeSpeech", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Close closes the underlying connection.
+func (c *Client) Close() error {
+	return c.rpcClient.Close()
+}
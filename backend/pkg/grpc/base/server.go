@@ -0,0 +1,63 @@
+package base
+
+import (
+	"fmt"
+	"net"
+	"net/rpc"
+)
+
+// backendServiceName is the name Server registers backend under and Client
+// dials through; net/rpc dispatches "ServiceName.Method" strings, so both
+// sides need to agree on it even though there's only ever one registered
+// service per worker process.
+const backendServiceName = "Backend"
+
+// Server exposes a Backend implementation over net/rpc on a single
+// listener, accepting one connection per Client.Dial the way net/rpc's own
+// examples do.
+type Server struct {
+	rpcServer *rpc.Server
+	listener  net.Listener
+}
+
+// NewServer registers backend and starts listening on network/address
+// (e.g. "unix", "/tmp/alice-minilm.sock", or "tcp", "127.0.0.1:0" to let the
+// OS pick a port). It does not start accepting connections; call Serve for
+// that.
+func NewServer(backend Backend, network, address string) (*Server, error) {
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName(backendServiceName, backend); err != nil {
+		return nil, fmt.Errorf("failed to register backend: %w", err)
+	}
+
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s %s: %w", network, address, err)
+	}
+
+	return &Server{rpcServer: rpcServer, listener: listener}, nil
+}
+
+// Addr returns the listener's actual address, useful when address was
+// "127.0.0.1:0" and the OS assigned the port.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Serve accepts connections until the listener is closed, handling each
+// one in its own goroutine the way net/rpc's documented usage does. It
+// always returns a non-nil error once the listener closes.
+func (s *Server) Serve() error {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return err
+		}
+		go s.rpcServer.ServeConn(conn)
+	}
+}
+
+// Close stops accepting new connections.
+func (s *Server) Close() error {
+	return s.listener.Close()
+}
@@ -0,0 +1,111 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// dialRetryInterval and dialRetryTimeout bound how long SupervisedWorker
+// waits for a freshly-spawned subprocess to start listening before giving
+// up - a cold model load can take a few seconds, but a worker that never
+// comes up (bad binary path, crash on startup) shouldn't hang Start forever.
+const (
+	dialRetryInterval = 100 * time.Millisecond
+	dialRetryTimeout  = 30 * time.Second
+)
+
+// SupervisedWorker launches a Backend as a subprocess, dials it once it's
+// listening, and watches the process so a crash is logged instead of
+// silently taking down whichever caller happens to be mid-request -
+// exactly the "a crash in a C-linked model doesn't take down the API
+// server" property an out-of-process backend is meant to provide.
+//
+// It deliberately does not auto-restart a crashed worker: models.Manager
+// callers get an error from their next Client call and can decide
+// whether recreating the worker is safe (an embeddings worker: yes: a TTS
+// worker mid-stream to a client: probably not without first making that
+// failure visible). Silent respawning would hide a real problem behind a
+// request that "just" got a little slower.
+type SupervisedWorker struct {
+	command string
+	args    []string
+	network string
+	address string
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	client *Client
+	exited chan struct{}
+}
+
+// NewSupervisedWorker describes (without starting) a worker subprocess:
+// command/args is the binary to run, network/address is where it's
+// expected to open its Server listener (e.g. "unix", a path under os.TempDir).
+func NewSupervisedWorker(command string, args []string, network, address string) *SupervisedWorker {
+	return &SupervisedWorker{command: command, args: args, network: network, address: address}
+}
+
+// Start spawns the subprocess and blocks until its Server is dialable (or
+// dialRetryTimeout elapses), returning a Client ready to use.
+func (w *SupervisedWorker) Start(ctx context.Context) (*Client, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	cmd := exec.CommandContext(context.Background(), w.command, w.args...)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start backend worker %s: %w", w.command, err)
+	}
+	w.cmd = cmd
+	w.exited = make(chan struct{})
+
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			log.Printf("backend worker %s exited: %v", w.command, err)
+		} else {
+			log.Printf("backend worker %s exited", w.command)
+		}
+		close(w.exited)
+	}()
+
+	deadline := time.Now().Add(dialRetryTimeout)
+	var lastErr error
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-w.exited:
+			return nil, fmt.Errorf("backend worker %s exited before becoming ready", w.command)
+		default:
+		}
+
+		client, err := Dial(w.network, w.address)
+		if err == nil {
+			w.client = client
+			return client, nil
+		}
+		lastErr = err
+		time.Sleep(dialRetryInterval)
+	}
+
+	return nil, fmt.Errorf("timed out waiting for backend worker %s to become ready: %w", w.command, lastErr)
+}
+
+// Stop terminates the subprocess and closes the client connection. Safe to
+// call even if Start never succeeded.
+func (w *SupervisedWorker) Stop() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.client != nil {
+		w.client.Close()
+		w.client = nil
+	}
+	if w.cmd == nil || w.cmd.Process == nil {
+		return nil
+	}
+	return w.cmd.Process.Kill()
+}